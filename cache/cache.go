@@ -0,0 +1,117 @@
+// Package cache contains an on-disk cache for the lookups done to Artifactory ('index.json' files and properties).
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// DirEnvVar is the environment variable with the directory to use for the cache. The cache is disabled if empty.
+	DirEnvVar = "CONAN_CENTER_CACHE_DIR"
+	// TTLEnvVar is the environment variable with the time (in seconds) entries are used without validating them.
+	TTLEnvVar = "CONAN_CENTER_CACHE_TTL"
+	// DefaultTTL is the time entries are used without validating them if `TTLEnvVar` is not set.
+	DefaultTTL = 5 * time.Minute
+)
+
+type entry struct {
+	Fetched time.Time `json:"fetched"`
+	Sha1    string    `json:"sha1,omitempty"`
+	Data    []byte    `json:"data"`
+}
+
+// Cache stores entries as files in the directory `Dir`. Entries fetched more than `TTL` ago are stale and need to be
+// validated (or fetched again) before using them.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+	now func() time.Time
+}
+
+// New returns a `Cache` using the given directory and TTL.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, now: time.Now}
+}
+
+// FromEnv returns the `Cache` configured using the environment variables `DirEnvVar` and `TTLEnvVar`, or nil if
+// the cache is disabled.
+func FromEnv() (*Cache, error) {
+	dir := os.Getenv(DirEnvVar)
+	if dir == "" {
+		return nil, nil
+	}
+	ttl := DefaultTTL
+	if value := os.Getenv(TTLEnvVar); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("Invalid value for '%s': '%s'", TTLEnvVar, value)
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	return New(dir, ttl), nil
+}
+
+var serverKeyPattern = regexp.MustCompile(`[^a-zA-Z0-9.\-]+`)
+
+// ServerKey returns the name used in the cache for the entries of the Artifactory server with the given `url`.
+func ServerKey(url string) string {
+	return serverKeyPattern.ReplaceAllString(url, "_")
+}
+
+func (cache *Cache) filename(key string) string {
+	return filepath.Join(cache.Dir, filepath.FromSlash(path.Clean("/"+key))) + ".cache"
+}
+
+func (cache *Cache) get(key string) (*entry, bool) {
+	content, err := ioutil.ReadFile(cache.filename(key))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(content, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func (cache *Cache) put(key string, e *entry) error {
+	content, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	filename := cache.filename(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filename), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = tmpFile.Write(content)
+	tmpFile.Close()
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), filename)
+}
+
+func (cache *Cache) fresh(e *entry) bool {
+	return cache.now().Sub(e.Fetched) < cache.TTL
+}
+
+// Clear removes all the entries under the given `prefix` (all of them if empty).
+func (cache *Cache) Clear(prefix string) error {
+	if prefix == "" {
+		return os.RemoveAll(cache.Dir)
+	}
+	return os.RemoveAll(filepath.Join(cache.Dir, filepath.FromSlash(path.Clean("/"+prefix))))
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// ServicesManager wraps an `artifactory.ArtifactoryServicesManager` and caches the 'index.json' files and the
+// properties lookups (single item searches and AQL queries). Stale 'index.json' files are validated using their
+// SHA1 checksum, stale properties are fetched again. Any write operation clears the entries of the server.
+type ServicesManager struct {
+	artifactory.ArtifactoryServicesManager
+	cache  *Cache
+	server string
+}
+
+// NewServicesManager returns a `ServicesManager` that stores the entries for `serviceManager` in `cache` under the
+// given `server` key (see `ServerKey`).
+func NewServicesManager(serviceManager artifactory.ArtifactoryServicesManager, cache *Cache, server string) *ServicesManager {
+	return &ServicesManager{ArtifactoryServicesManager: serviceManager, cache: cache, server: server}
+}
+
+// ReadRemoteFile returns the contents of the remote file `readPath`, using the cache for 'index.json' files.
+func (sm *ServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser, error) {
+	if path.Base(readPath) != "index.json" {
+		return sm.ArtifactoryServicesManager.ReadRemoteFile(readPath)
+	}
+
+	key := sm.server + "/files/" + readPath
+	if e, ok := sm.cache.get(key); ok {
+		if sm.cache.fresh(e) {
+			log.Debug(fmt.Sprintf("Cache hit for '%s'", readPath))
+			return ioutil.NopCloser(bytes.NewReader(e.Data)), nil
+		}
+		if sm.validate(readPath, e.Sha1) {
+			log.Debug(fmt.Sprintf("Cache entry for '%s' is still valid", readPath))
+			e.Fetched = sm.cache.now()
+			sm.store(key, e)
+			return ioutil.NopCloser(bytes.NewReader(e.Data)), nil
+		}
+	}
+
+	reader, err := sm.ArtifactoryServicesManager.ReadRemoteFile(readPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha1.Sum(data)
+	sm.store(key, &entry{Fetched: sm.cache.now(), Sha1: hex.EncodeToString(checksum[:]), Data: data})
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// validate returns true if the checksum of the remote file `readPath` matches the given one.
+func (sm *ServicesManager) validate(readPath string, sha1 string) bool {
+	if sha1 == "" {
+		return false
+	}
+	params := services.NewSearchParams()
+	params.Pattern = readPath
+	params.Recursive = false
+	reader, err := sm.ArtifactoryServicesManager.SearchFiles(params)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Cannot validate cache entry for '%s': %s", readPath, err))
+		return false
+	}
+	defer reader.Close()
+	resultItem := new(servicesUtils.ResultItem)
+	if reader.NextRecord(resultItem) != nil {
+		return false
+	}
+	return resultItem.Actual_Sha1 == sha1
+}
+
+// SearchFiles runs the search given by `params`, using the cache for the lookups of a single item (no wildcards, not
+// recursive and including folders) like the ones used to read properties.
+func (sm *ServicesManager) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
+	if params.ArtifactoryCommonParams == nil || params.Recursive || !params.IncludeDirs || strings.ContainsAny(params.Pattern, "*?") {
+		return sm.ArtifactoryServicesManager.SearchFiles(params)
+	}
+
+	key := sm.server + "/search/" + params.Pattern
+	if e, ok := sm.cache.get(key); ok && sm.cache.fresh(e) {
+		log.Debug(fmt.Sprintf("Cache hit for search '%s'", params.Pattern))
+		return newContentReader(e.Data)
+	}
+
+	reader, err := sm.ArtifactoryServicesManager.SearchFiles(params)
+	if err != nil || reader.GetFilePath() == "" {
+		return reader, err
+	}
+	data, err := ioutil.ReadFile(reader.GetFilePath())
+	reader.Close()
+	if err != nil {
+		return nil, err
+	}
+	sm.store(key, &entry{Fetched: sm.cache.now(), Data: data})
+	return newContentReader(data)
+}
+
+// Aql runs the given AQL query, using the cache for the results.
+func (sm *ServicesManager) Aql(aql string) (io.ReadCloser, error) {
+	checksum := sha1.Sum([]byte(aql))
+	key := sm.server + "/aql/" + hex.EncodeToString(checksum[:])
+	if e, ok := sm.cache.get(key); ok && sm.cache.fresh(e) {
+		log.Debug("Cache hit for AQL query")
+		return ioutil.NopCloser(bytes.NewReader(e.Data)), nil
+	}
+
+	reader, err := sm.ArtifactoryServicesManager.Aql(aql)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	sm.store(key, &entry{Fetched: sm.cache.now(), Data: data})
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// SetProps sets properties and clears the cache.
+func (sm *ServicesManager) SetProps(params services.PropsParams) (int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.SetProps(params)
+}
+
+// DeleteProps deletes properties and clears the cache.
+func (sm *ServicesManager) DeleteProps(params services.PropsParams) (int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.DeleteProps(params)
+}
+
+// UploadFiles uploads files and clears the cache.
+func (sm *ServicesManager) UploadFiles(params ...services.UploadParams) (int, int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.UploadFiles(params...)
+}
+
+// DeleteFiles deletes files and clears the cache.
+func (sm *ServicesManager) DeleteFiles(reader *content.ContentReader) (int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.DeleteFiles(reader)
+}
+
+// Copy copies files and clears the cache.
+func (sm *ServicesManager) Copy(params services.MoveCopyParams) (int, int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.Copy(params)
+}
+
+// Move moves files and clears the cache.
+func (sm *ServicesManager) Move(params services.MoveCopyParams) (int, int, error) {
+	defer sm.invalidate()
+	return sm.ArtifactoryServicesManager.Move(params)
+}
+
+func (sm *ServicesManager) store(key string, e *entry) {
+	if err := sm.cache.put(key, e); err != nil {
+		log.Warn(fmt.Sprintf("Cannot write cache entry: %s", err))
+	}
+}
+
+func (sm *ServicesManager) invalidate() {
+	if err := sm.cache.Clear(sm.server); err != nil {
+		log.Warn(fmt.Sprintf("Cannot clear cache: %s", err))
+	}
+}
+
+// newContentReader returns a reader for the search results in `data`. It uses a temporary file that is removed
+// when the reader is closed.
+func newContentReader(data []byte) (*content.ContentReader, error) {
+	tmpFile, err := ioutil.TempFile("", "search-*.json")
+	if err != nil {
+		return nil, err
+	}
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return nil, err
+	}
+	return content.NewContentReader(tmpFile.Name(), content.DefaultKey), nil
+}
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/utils/log"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	contentIndex = `{"reference": "b2/4.0.0@_/_", "revisions": []}`
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+type MockArtifactoryServicesManager struct {
+	artifactory.EmptyArtifactoryServicesManager
+	reads    int
+	searches int
+	queries  int
+	sha1     string
+}
+
+func (esm *MockArtifactoryServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser, error) {
+	esm.reads++
+	return ioutil.NopCloser(strings.NewReader(contentIndex)), nil
+}
+
+func (esm *MockArtifactoryServicesManager) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
+	esm.searches++
+	tmpFile, _ := ioutil.TempFile(os.TempDir(), "prefix-")
+	tmpFile.WriteString(`{"results": [{"repo": "repo", "path": "_/b2/4.0.0/_", "name": "index.json", "type": "file", "actual_sha1": "` + esm.sha1 + `"}]}`)
+	tmpFile.Close()
+	return content.NewContentReader(tmpFile.Name(), "results"), nil
+}
+
+func (esm *MockArtifactoryServicesManager) Aql(aql string) (io.ReadCloser, error) {
+	esm.queries++
+	return ioutil.NopCloser(strings.NewReader(`{"results": []}`)), nil
+}
+
+func (esm *MockArtifactoryServicesManager) SetProps(params services.PropsParams) (int, error) {
+	return 1, nil
+}
+
+func newTestCache(t *testing.T) (*Cache, *time.Time) {
+	dir, err := ioutil.TempDir("", "cache-")
+	assert.Nil(t, err)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := New(dir, time.Minute)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func readAll(reader io.ReadCloser, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	defer reader.Close()
+	b, _ := ioutil.ReadAll(reader)
+	return string(b)
+}
+
+func TestReadRemoteFile(t *testing.T) {
+	cache, now := newTestCache(t)
+	defer os.RemoveAll(cache.Dir)
+	checksum := sha1.Sum([]byte(contentIndex))
+	mock := MockArtifactoryServicesManager{sha1: hex.EncodeToString(checksum[:])}
+	servicesManager := NewServicesManager(&mock, cache, ServerKey("https://example.com/artifactory"))
+
+	// First read downloads the file, second one uses the cache
+	assert.Equal(t, contentIndex, readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/index.json")))
+	assert.Equal(t, contentIndex, readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/index.json")))
+	assert.Equal(t, 1, mock.reads)
+	assert.Equal(t, 0, mock.searches)
+
+	// Stale entry is validated using the checksum
+	*now = now.Add(2 * time.Minute)
+	assert.Equal(t, contentIndex, readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/index.json")))
+	assert.Equal(t, 1, mock.reads)
+	assert.Equal(t, 1, mock.searches)
+
+	// Stale entry with a different checksum is downloaded again
+	*now = now.Add(2 * time.Minute)
+	mock.sha1 = "other"
+	assert.Equal(t, contentIndex, readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/index.json")))
+	assert.Equal(t, 2, mock.reads)
+	assert.Equal(t, 2, mock.searches)
+
+	// Other files are not cached
+	readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/rrev/export/conanfile.py"))
+	readAll(servicesManager.ReadRemoteFile("repo/_/b2/4.0.0/_/rrev/export/conanfile.py"))
+	assert.Equal(t, 4, mock.reads)
+}
+
+func TestSearchFiles(t *testing.T) {
+	cache, now := newTestCache(t)
+	defer os.RemoveAll(cache.Dir)
+	mock := MockArtifactoryServicesManager{}
+	servicesManager := NewServicesManager(&mock, cache, "server")
+
+	params := services.NewSearchParams()
+	params.Pattern = "repo/_/b2/4.0.0/_/rrev"
+	params.IncludeDirs = true
+	for i := 0; i < 2; i++ {
+		reader, err := servicesManager.SearchFiles(params)
+		assert.Nil(t, err)
+		resultItem := new(servicesUtils.ResultItem)
+		assert.Nil(t, reader.NextRecord(resultItem))
+		assert.Equal(t, "index.json", resultItem.Name)
+		reader.Close()
+	}
+	assert.Equal(t, 1, mock.searches)
+
+	*now = now.Add(2 * time.Minute)
+	reader, err := servicesManager.SearchFiles(params)
+	assert.Nil(t, err)
+	reader.Close()
+	assert.Equal(t, 2, mock.searches)
+
+	// Searches with wildcards are not cached
+	params.Pattern = "repo/*/b2/*"
+	for i := 0; i < 2; i++ {
+		reader, err := servicesManager.SearchFiles(params)
+		assert.Nil(t, err)
+		reader.Close()
+	}
+	assert.Equal(t, 4, mock.searches)
+}
+
+func TestAqlAndInvalidate(t *testing.T) {
+	cache, _ := newTestCache(t)
+	defer os.RemoveAll(cache.Dir)
+	mock := MockArtifactoryServicesManager{}
+	servicesManager := NewServicesManager(&mock, cache, "server")
+
+	readAll(servicesManager.Aql(`items.find({"repo":"repo"})`))
+	readAll(servicesManager.Aql(`items.find({"repo":"repo"})`))
+	assert.Equal(t, 1, mock.queries)
+	readAll(servicesManager.Aql(`items.find({"repo":"other"})`))
+	assert.Equal(t, 2, mock.queries)
+
+	// Write operations clear the cache
+	_, err := servicesManager.SetProps(services.NewPropsParams())
+	assert.Nil(t, err)
+	readAll(servicesManager.Aql(`items.find({"repo":"repo"})`))
+	assert.Equal(t, 3, mock.queries)
+}
+
+func TestFromEnv(t *testing.T) {
+	os.Setenv(DirEnvVar, "")
+	cache, err := FromEnv()
+	assert.Nil(t, err)
+	assert.Nil(t, cache)
+
+	os.Setenv(DirEnvVar, "/tmp/cache")
+	defer os.Setenv(DirEnvVar, "")
+	cache, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, "/tmp/cache", cache.Dir)
+	assert.Equal(t, DefaultTTL, cache.TTL)
+
+	os.Setenv(TTLEnvVar, "60")
+	defer os.Setenv(TTLEnvVar, "")
+	cache, err = FromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, cache.TTL)
+
+	os.Setenv(TTLEnvVar, "never")
+	_, err = FromEnv()
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid value for 'CONAN_CENTER_CACHE_TTL': 'never'", err.Error())
+}
+
+func TestServerKey(t *testing.T) {
+	assert.Equal(t, "https_example.com_artifactory_", ServerKey("https://example.com/artifactory/"))
+}
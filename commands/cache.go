@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/cache"
+)
+
+// GetCacheCommand returns object description for the command 'cache'
+func GetCacheCommand() components.Command {
+	return components.Command{
+		Name:        "cache",
+		Description: "Manage the local cache for 'index.json' files and properties",
+		Aliases:     []string{},
+		Arguments:   getCacheArguments(),
+		Flags:       getCacheFlags(),
+		EnvVars:     getCacheEnvVars(),
+		Action: func(c *components.Context) error {
+			return cacheCmd(c)
+		},
+	}
+}
+
+func getCacheFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If specified, only the entries for this server are removed.",
+			DefaultValue: "",
+		},
+	}
+}
+
+func getCacheArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "action",
+			Description: "Action to run, only 'clear' is supported",
+		},
+	}
+}
+
+func getCacheEnvVars() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        cache.DirEnvVar,
+			Description: "Directory to cache 'index.json' files and properties lookups. If empty, the cache is disabled",
+		},
+		{
+			Name:        cache.TTLEnvVar,
+			Description: "Time (in seconds) cached entries are used without validating them",
+			Default:     strconv.Itoa(int(cache.DefaultTTL.Seconds())),
+		},
+	}
+}
+
+func cacheCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	if c.Arguments[0] != "clear" {
+		return fmt.Errorf("Invalid action '%s'. Expected: clear", c.Arguments[0])
+	}
+
+	diskCache, err := cache.FromEnv()
+	if err != nil {
+		return err
+	}
+	if diskCache == nil {
+		return fmt.Errorf("Cache is disabled, set '%s' to enable it", cache.DirEnvVar)
+	}
+
+	prefix := ""
+	if serverID := c.GetStringFlagValue("server-id"); serverID != "" {
+		rtDetails, err := commands.GetConfig(serverID, true)
+		if err != nil {
+			return err
+		}
+		prefix = cache.ServerKey(rtDetails.Url)
+	}
+	if err := diskCache.Clear(prefix); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Cache in '%s' cleared", diskCache.Dir))
+	return nil
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/manage"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+const (
+	dateLayout = "2006-01-02"
+)
+
+// GetCleanupCommand returns object description for the command 'cleanup'
+func GetCleanupCommand() components.Command {
+	return components.Command{
+		Name:        "cleanup",
+		Description: "Remove stale recipe and package revisions from a repository",
+		Aliases:     []string{"c"},
+		Arguments:   getCleanupArguments(),
+		Flags:       getCleanupFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return cleanupCmd(c)
+		},
+	}
+}
+
+func getCleanupFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to clean (only the name). If not set, it will work on all references",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "keep",
+			Description:  "Number of latest revisions to keep",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "before",
+			Description:  "Remove revisions created before this date (format YYYY-MM-DD)",
+			DefaultValue: "",
+		},
+		components.BoolFlag{
+			Name:         "packages",
+			Description:  "If specified, it will remove also stale package revisions from the recipe revisions that are kept",
+			DefaultValue: false,
+		},
+		components.BoolFlag{
+			Name:         "confirm",
+			Description:  "If specified, it will delete the revisions. Otherwise it only lists them",
+			DefaultValue: false,
+		},
+		getStrictFlag(),
+		getLenientFlag(),
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getCleanupArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+	}
+}
+
+func getRetentionPolicy(c *components.Context) (*manage.RetentionPolicy, error) {
+	policy := &manage.RetentionPolicy{}
+	if value := c.GetStringFlagValue("keep"); value != "" {
+		keep, err := strconv.Atoi(value)
+		if err != nil || keep < 1 {
+			return nil, fmt.Errorf("Invalid value for 'keep': '%s'", value)
+		}
+		policy.Keep = keep
+	}
+	if value := c.GetStringFlagValue("before"); value != "" {
+		before, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for 'before': '%s'", value)
+		}
+		policy.Before = before
+	}
+	if policy.Keep == 0 && policy.Before.IsZero() {
+		return nil, errors.New("At least one of 'keep' or 'before' is required")
+	}
+	return policy, nil
+}
+
+func cleanupCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	policy, err := getRetentionPolicy(c)
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command cleanup")
+	referenceName := c.GetStringFlagValue("ref-name")
+	log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+	confirm := c.GetBoolFlagValue("confirm")
+
+	references, err := search.SearchReferences(serviceManager, repository, referenceName, true, nil, options)
+	if err != nil {
+		return err
+	}
+
+	// Only the revisions found count for the policy, the latest one is resolved like in the search
+	resolver := search.NewRevisionResolver(serviceManager, repository, options)
+	removed := 0
+	for _, reference := range references {
+		basePath := repository + "/" + reference.RtPath(false)
+		rtRevisions, err := resolver.ReferenceRevisions(reference)
+		if err != nil {
+			return err
+		}
+		stale := policy.StaleFoundRevisions(rtRevisions)
+		for _, revision := range stale {
+			log.Output(fmt.Sprintf("%s#%s (%s)", reference.ToString(false), revision.Revision, revision.Time.Format(time.RFC3339)))
+		}
+		if confirm && len(stale) > 0 {
+			if err := manage.RemoveRevisions(serviceManager, basePath, stale); err != nil {
+				return err
+			}
+		}
+		removed += len(stale)
+
+		if c.GetBoolFlagValue("packages") {
+			for _, revision := range rtRevisions {
+				if isRevisionIn(revision, stale) {
+					continue
+				}
+				ref := reference
+				ref.Revision = revision.Revision
+				n, err := cleanupPackages(serviceManager, resolver, repository, ref, policy, confirm)
+				if err != nil {
+					return err
+				}
+				removed += n
+			}
+		}
+	}
+
+	if confirm {
+		log.Info(fmt.Sprintf("Removed %d revisions", removed))
+	} else {
+		log.Info(fmt.Sprintf("Found %d stale revisions (use '--confirm' to remove them)", removed))
+	}
+	return nil
+}
+
+// cleanupPackages lists (and removes if `confirm`) the stale package revisions of the given reference `ref`, only the
+// package revisions found by the `resolver` count for the `policy`.
+func cleanupPackages(serviceManager artifactory.ArtifactoryServicesManager, resolver *search.RevisionResolver, repository string, ref types.Reference, policy *manage.RetentionPolicy, confirm bool) (int, error) {
+	packages, err := search.SearchReferencePackages(serviceManager, repository, ref)
+	if err != nil {
+		return 0, err
+	}
+	packageIds := []string{}
+	seen := make(map[string]bool)
+	for _, pkg := range packages {
+		if !seen[pkg.PackageId] {
+			seen[pkg.PackageId] = true
+			packageIds = append(packageIds, pkg.PackageId)
+		}
+	}
+	sort.Strings(packageIds)
+
+	removed := 0
+	for _, packageID := range packageIds {
+		pkg := types.Package{Ref: ref, PackageId: packageID}
+		basePath := repository + "/" + pkg.RtPath(false)
+		rtRevisions, err := resolver.PackageRevisions(pkg)
+		if err != nil {
+			return removed, err
+		}
+		stale := policy.StaleFoundRevisions(rtRevisions)
+		for _, revision := range stale {
+			pkg.Revision = revision.Revision
+			log.Output(fmt.Sprintf("%s (%s)", pkg.ToString(true), revision.Time.Format(time.RFC3339)))
+		}
+		if confirm && len(stale) > 0 {
+			if err := manage.RemoveRevisions(serviceManager, basePath, stale); err != nil {
+				return removed, err
+			}
+		}
+		removed += len(stale)
+	}
+	return removed, nil
+}
+
+func isRevisionIn(revision types.RtRevisionsData, revisions []types.RtRevisionsData) bool {
+	for _, r := range revisions {
+		if r.Revision == revision.Revision {
+			return true
+		}
+	}
+	return false
+}
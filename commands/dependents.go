@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/graph"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetDependentsCommand returns object description for the command 'dependents'
+func GetDependentsCommand() components.Command {
+	return components.Command{
+		Name:        "dependents",
+		Description: "Return the references that require, directly or transitively, a given one",
+		Aliases:     []string{"dep"},
+		Arguments:   getDependentsArguments(),
+		Flags:       getDependentsFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return dependentsCmd(c)
+		},
+	}
+}
+
+func getDependentsFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.BoolFlag{
+			Name:         "packages",
+			Description:  "If specified, it will consider also the 'requires' properties of the packages",
+			DefaultValue: false,
+		},
+		components.BoolFlag{
+			Name:         "direct",
+			Description:  "If specified, it will return only the direct dependents",
+			DefaultValue: false,
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text or json",
+			DefaultValue: string(output.Text),
+		},
+		getStrictFlag(),
+		getLenientFlag(),
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getDependentsArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+		{
+			Name:        "name",
+			Description: "Name of the required reference, optionally followed by the version (<name>[/<version>]), or a full reference (<name>/<version>@<user>/<channel>)",
+		},
+	}
+}
+
+func dependentsCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 {
+		return errors.New("Wrong number of arguments. Expected: 2, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON)
+	if err != nil {
+		return err
+	}
+
+	target, err := parseDependentsTarget(c.Arguments[1])
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command dependents")
+	log.Info(fmt.Sprintf(" - target: %s", c.Arguments[1]))
+
+	// Requirements of the latest recipe revisions (and their packages)
+	references, err := search.SearchReferences(serviceManager, repository, "", true, nil, options)
+	if err != nil {
+		return err
+	}
+	latest := make(map[string]*graph.Recipe)
+	for i := range references {
+		latest[references[i].ToString(true)] = &graph.Recipe{Reference: references[i]}
+	}
+
+	referencesProperties, err := search.ReadRepositoryReferencesProperties(serviceManager, repository, "")
+	if err != nil {
+		return err
+	}
+	for _, referenceProperties := range referencesProperties {
+		if recipe, ok := latest[referenceProperties.Reference.ToString(true)]; ok {
+			recipe.Requires = append(recipe.Requires, requiresValues(referenceProperties.Properties)...)
+		}
+	}
+
+	if c.GetBoolFlagValue("packages") {
+		packagesProperties, err := search.ReadRepositoryPackagesProperties(serviceManager, repository, "")
+		if err != nil {
+			return err
+		}
+		for _, packageProperties := range packagesProperties {
+			if recipe, ok := latest[packageProperties.Package.Ref.ToString(true)]; ok {
+				recipe.Requires = append(recipe.Requires, requiresValues(packageProperties.Properties)...)
+			}
+		}
+	}
+
+	recipes := []graph.Recipe{}
+	for i := range references {
+		recipes = append(recipes, *latest[references[i].ToString(true)])
+	}
+	dependents := []graph.Dependent{}
+	for _, dependent := range graph.Dependents(recipes, *target) {
+		if dependent.Depth == 1 || !c.GetBoolFlagValue("direct") {
+			dependents = append(dependents, dependent)
+		}
+	}
+
+	if format != output.Text {
+		return printItems(format, dependents)
+	}
+	log.Output(fmt.Sprintf("Dependents of '%s' (%d):", c.Arguments[1], len(dependents)))
+	for _, dependent := range dependents {
+		log.Output(fmt.Sprintf("  [%d] %s -> %s", dependent.Depth, dependent.Reference, strings.Join(dependent.Requires, ", ")))
+	}
+	return nil
+}
+
+// parseDependentsTarget parses the reference to look for: '<name>', '<name>/<version>' or a full reference with user
+// and channel.
+func parseDependentsTarget(value string) (*types.Reference, error) {
+	if strings.Contains(value, "@") {
+		target, err := types.ParseStringReference(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s'. Expected: <name>[/<version>] or <name>/<version>@<user>/<channel>", value)
+		}
+		target.Revision = ""
+		return target, nil
+	}
+	parts := strings.SplitN(value, "/", 2)
+	target := types.Reference{Name: parts[0]}
+	if len(parts) == 2 {
+		target.Version = parts[1]
+	}
+	if target.Name == "" || (len(parts) == 2 && target.Version == "") {
+		return nil, fmt.Errorf("Invalid value '%s'. Expected: <name>[/<version>] or <name>/<version>@<user>/<channel>", value)
+	}
+	return &target, nil
+}
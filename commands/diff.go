@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/diff"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetDiffCommand returns object description for the command 'diff'
+func GetDiffCommand() components.Command {
+	return components.Command{
+		Name:        "diff",
+		Description: "Compare two repositories or two revisions of a Conan reference",
+		Aliases:     []string{"d"},
+		Arguments:   getDiffArguments(),
+		Flags:       getDiffFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return diffCmd(c)
+		},
+	}
+}
+
+func getDiffFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "other-server-id",
+			Description:  "Artifactory server ID for the other repository. If not specified, the same as 'server-id' is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to compare (only the name). If not set, it will compare all references",
+			DefaultValue: "",
+		},
+		getVersionFlag(),
+		components.BoolFlag{
+			Name:         "properties",
+			Description:  "If specified, it will compare also the properties of the common recipe revisions",
+			DefaultValue: false,
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text or json",
+			DefaultValue: string(output.Text),
+		},
+		getStrictFlag(),
+		getLenientFlag(),
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getDiffArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+		{
+			Name:        "other",
+			Description: "Artifactory repository to compare with or, to compare two revisions, the Conan reference to work with (use v2 style, without trailing @)",
+		},
+		{
+			Name:        "other-reference",
+			Description: "[Optional] Conan reference to compare with (use v2 style, without trailing @). If no revision is given, it will use latest one",
+		},
+	}
+}
+
+func diffCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 && len(c.Arguments) != 3 {
+		return errors.New("Wrong number of arguments. Expected: 2 or 3, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON)
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	var items []diff.Item
+	if len(c.Arguments) == 2 {
+		items, err = diffRepositories(c, options)
+	} else {
+		items, err = diffRevisions(c, options)
+	}
+	if err != nil {
+		return err
+	}
+
+	if format == output.Text {
+		for _, item := range items {
+			log.Output(item.String())
+		}
+	} else if err := printItems(format, items); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Found %d differences", len(items)))
+	return nil
+}
+
+// diffRepositories compares references, recipe revisions and packages (and properties if requested) of two repositories.
+func diffRepositories(c *components.Context, options *search.Options) ([]diff.Item, error) {
+	repository := c.Arguments[0]
+	otherRepository := c.Arguments[1]
+	log.Info(fmt.Sprintf("Compare repositories %s -> %s", repository, otherRepository))
+	serverID := c.GetStringFlagValue("server-id")
+	serviceManager, err := createServiceManager(serverID, repository)
+	if err != nil {
+		return nil, err
+	}
+	otherServerID := c.GetStringFlagValue("other-server-id")
+	if otherServerID == "" {
+		otherServerID = serverID
+	}
+	otherServiceManager, err := createServiceManager(otherServerID, otherRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Command diff")
+	referenceName := c.GetStringFlagValue("ref-name")
+	log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+	versionRange, err := getVersionRange(c)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := search.SearchReferences(serviceManager, repository, referenceName, false, versionRange, options)
+	if err != nil {
+		return nil, err
+	}
+	otherReferences, err := search.SearchReferences(otherServiceManager, otherRepository, referenceName, false, versionRange, options)
+	if err != nil {
+		return nil, err
+	}
+	items := diff.References(references, otherReferences)
+
+	packages, err := searchRevisionPackages(serviceManager, repository, referenceName, versionRange, options)
+	if err != nil {
+		return nil, err
+	}
+	otherPackages, err := searchRevisionPackages(otherServiceManager, otherRepository, referenceName, versionRange, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare packages (and properties) of the common recipe revisions
+	otherRevisions := make(map[string]bool)
+	for i := range otherReferences {
+		otherRevisions[otherReferences[i].ToString(true)] = true
+	}
+	for i := range references {
+		ref := references[i]
+		if !otherRevisions[ref.ToString(true)] {
+			continue
+		}
+		items = append(items, diff.Packages(packages[ref.ToString(true)], otherPackages[ref.ToString(true)])...)
+
+		if c.GetBoolFlagValue("properties") {
+			properties, err := search.ReadReferenceProperties(serviceManager, repository, ref)
+			if err != nil {
+				return nil, err
+			}
+			otherProperties, err := search.ReadReferenceProperties(otherServiceManager, otherRepository, ref)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, diff.Properties(ref.ToString(true), properties, otherProperties)...)
+		}
+	}
+	return items, nil
+}
+
+// diffRevisions compares properties and packages of two revisions of a reference in the same repository, missing
+// revisions are resolved according to the `options`.
+func diffRevisions(c *components.Context, options *search.Options) ([]diff.Item, error) {
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Command diff")
+	references := []*types.Reference{}
+	for _, reference := range c.Arguments[1:] {
+		log.Info(fmt.Sprintf(" - input reference: %s", reference))
+		rtReference, err := types.ParseStringReference(reference)
+		if err != nil {
+			return nil, err
+		}
+		if rtReference.Revision == "" { // Search for the latest revision
+			rtReference.Revision, err = search.NewRevisionResolver(serviceManager, repository, options).ReferenceRevision(*rtReference)
+			if err != nil {
+				return nil, err
+			}
+		}
+		log.Info(" - working reference:", rtReference.ToString(true))
+		references = append(references, rtReference)
+	}
+	ref, otherRef := *references[0], *references[1]
+
+	properties, err := search.ReadReferenceProperties(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	otherProperties, err := search.ReadReferenceProperties(serviceManager, repository, otherRef)
+	if err != nil {
+		return nil, err
+	}
+	items := diff.Properties(ref.ToString(true), properties, otherProperties)
+
+	packages, err := search.SearchReferencePackages(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	otherPackages, err := search.SearchReferencePackages(serviceManager, repository, otherRef)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, diff.Packages(packages, otherPackages)...), nil
+}
+
+// searchRevisionPackages returns all the packages in the `repository` grouped by recipe revision (the reference with revision).
+func searchRevisionPackages(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, versionRange *types.VersionRange, options *search.Options) (map[string][]types.Package, error) {
+	packages, err := search.SearchPackages(serviceManager, repository, referenceName, false, false, versionRange, options)
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[string][]types.Package)
+	for i := range packages {
+		key := packages[i].Ref.ToString(true)
+		grouped[key] = append(grouped[key], packages[i])
+	}
+	return grouped, nil
+}
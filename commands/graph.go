@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/graph"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetGraphCommand returns object description for the command 'graph'
+func GetGraphCommand() components.Command {
+	return components.Command{
+		Name:        "graph",
+		Description: "Return the dependency graph of a Conan reference using the 'requires' properties",
+		Aliases:     []string{"g"},
+		Arguments:   getGraphArguments(),
+		Flags:       getGraphFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return graphCmd(c)
+		},
+	}
+}
+
+func getGraphFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text, json or dot",
+			DefaultValue: string(output.Text),
+		},
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getGraphArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+		{
+			Name:        "reference",
+			Description: "Conan reference to work with (use v2 style, without trailing @). If no revision is given, it will use latest one",
+		},
+	}
+}
+
+func graphCmd(c *components.Context) error {
+	if len(c.Arguments) != 2 {
+		return errors.New("Wrong number of arguments. Expected: 2, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON, output.DOT)
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command graph")
+	reference := c.Arguments[1]
+	log.Info(fmt.Sprintf(" - input reference: %s", reference))
+	rtReference, err := types.ParseStringReference(reference)
+	if err != nil {
+		return err
+	}
+
+	dependencyGraph := graph.Build(*rtReference, func(ref types.Reference) (*graph.Recipe, error) {
+		return resolveRecipe(serviceManager, repository, ref, options)
+	})
+	if root := dependencyGraph.Node(dependencyGraph.Root); root.Missing {
+		return fmt.Errorf("Cannot resolve reference '%s': %s", reference, root.Error)
+	}
+	for _, node := range dependencyGraph.Missing() {
+		log.Warn(fmt.Sprintf("Missing requirement '%s': %s", node.Reference, node.Error))
+	}
+	for _, conflict := range dependencyGraph.Conflicts {
+		required := []string{}
+		for ref, requiredBy := range conflict.References {
+			required = append(required, fmt.Sprintf("%s (by %s)", ref, strings.Join(requiredBy, ", ")))
+		}
+		sort.Strings(required)
+		log.Warn(fmt.Sprintf("Conflict for '%s': %s", conflict.Name, strings.Join(required, "; ")))
+	}
+	for _, cycle := range dependencyGraph.Cycles {
+		log.Warn(fmt.Sprintf("Cycle found: %s", strings.Join(cycle, " -> ")))
+	}
+
+	switch format {
+	case output.DOT:
+		log.Output(dependencyGraph.DOT())
+	case output.Text:
+		for _, line := range dependencyGraph.Tree() {
+			log.Output(line)
+		}
+	default:
+		return printItems(format, dependencyGraph)
+	}
+	return nil
+}
+
+// resolveRecipe returns the given reference (using the latest revision, according to the `options`, if it has no
+// revision) and the values of its 'requires' properties.
+func resolveRecipe(serviceManager artifactory.ArtifactoryServicesManager, repository string, ref types.Reference, options *search.Options) (*graph.Recipe, error) {
+	if ref.Revision == "" {
+		revision, err := search.NewRevisionResolver(serviceManager, repository, options).ReferenceRevision(ref)
+		if err != nil {
+			return nil, err
+		}
+		ref.Revision = revision
+	}
+	log.Debug(fmt.Sprintf("Resolved '%s' to '%s'", ref.ToString(false), ref.ToString(true)))
+
+	properties, err := search.ReadReferenceProperties(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &graph.Recipe{Reference: ref, Requires: requiresValues(properties)}, nil
+}
+
+// requiresValues returns the non-empty values of the 'requires' property in `properties`.
+func requiresValues(properties []servicesUtils.Property) []string {
+	values := []string{}
+	for _, prop := range properties {
+		if prop.Key == "requires" && prop.Value != "" {
+			values = append(values, prop.Value)
+		}
+	}
+	return values
+}
@@ -1,23 +1,27 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"os"
 	"strconv"
+	"time"
 
-	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
-	"github.com/jfrog/jfrog-cli-core/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/plugins/components"
-	"github.com/jfrog/jfrog-client-go/artifactory/services"
-	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jgsogo/jcli-conan-center/indexer"
 	"github.com/jgsogo/jcli-conan-center/search"
 	"github.com/jgsogo/jcli-conan-center/types"
 )
 
+const (
+	indexerTokenEnvVar = "CONAN_CENTER_INDEXER_TOKEN"
+)
+
+// GetIndexReferenceCommand returns object description for the command 'index-reference'
 func GetIndexReferenceCommand() components.Command {
 	return components.Command{
 		Name:        "index-reference",
@@ -25,7 +29,7 @@ func GetIndexReferenceCommand() components.Command {
 		Aliases:     []string{"i"},
 		Arguments:   getIndexReferenceArguments(),
 		Flags:       getIndexReferenceFlags(),
-		EnvVars:     []components.EnvVar{},
+		EnvVars:     getIndexerEnvVars(),
 		Action: func(c *components.Context) error {
 			return indexReferenceCmd(c)
 		},
@@ -33,7 +37,7 @@ func GetIndexReferenceCommand() components.Command {
 }
 
 func getIndexReferenceFlags() []components.Flag {
-	return []components.Flag{
+	return append([]components.Flag{
 		components.StringFlag{
 			Name:         "server-id",
 			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
@@ -44,6 +48,44 @@ func getIndexReferenceFlags() []components.Flag {
 			Description:  "Force argument in the indexer call",
 			DefaultValue: false,
 		},
+		getThreadsFlag(),
+	}, getIndexerFlags()...)
+}
+
+func getIndexerFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "endpoint",
+			Description:  "URL of the indexer. If not specified, the JSON payload is printed",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "retries",
+			Description:  "Number of retries if the indexer call fails",
+			DefaultValue: strconv.Itoa(indexer.DefaultRetries),
+		},
+		components.StringFlag{
+			Name:         "timeout",
+			Description:  "Timeout (in seconds) for each indexer call",
+			DefaultValue: strconv.Itoa(int(indexer.DefaultTimeout.Seconds())),
+		},
+		components.BoolFlag{
+			Name:         "dry-run",
+			Description:  "If specified, the JSON payload is printed instead of being sent to the indexer",
+			DefaultValue: false,
+		},
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getIndexerEnvVars() []components.EnvVar {
+	return []components.EnvVar{
+		{
+			Name:        indexerTokenEnvVar,
+			Default:     "",
+			Description: "Token to authenticate against the indexer endpoint",
+		},
 	}
 }
 
@@ -55,7 +97,7 @@ func getIndexReferenceArguments() []components.Argument {
 		},
 		{
 			Name:        "reference",
-			Description: "Conan reference to work with (use v2 style, without trailing @). If no revision is given, it will use latest one",
+			Description: "Conan reference to work with (use v2 style, without trailing @). It accepts a revision or a selector: #latest, #latest~N, @YYYY-MM-DD or #all. If no revision is given, it will use latest one",
 		},
 	}
 }
@@ -65,83 +107,134 @@ func indexReferenceCmd(c *components.Context) error {
 		return errors.New("Wrong number of arguments. Expected: 2, " + "Received: " + strconv.Itoa(len(c.Arguments)))
 	}
 
-	rtDetails, err := commands.GetConfig(c.GetStringFlagValue("server-id"), true)
+	options, reportSkipped, err := setupSearch(c)
 	if err != nil {
-		log.Error(err)
 		return err
 	}
+	defer reportSkipped()
 
-	// Check if repository exists
+	// Check if repository exists and create services manager
 	repository := c.Arguments[0]
 	log.Output("Work on repository", repository)
-	artAuth, err := rtDetails.CreateArtAuthConfig()
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
 	if err != nil {
 		return err
 	}
-	err = utils.CheckIfRepoExists(repository, artAuth)
+
+	log.Info("Command index-reference")
+	reference := c.Arguments[1]
+	log.Info(fmt.Sprintf(" - input reference: %s", reference))
+
+	rtReferences, err := selectReferences(serviceManager, repository, reference, options)
 	if err != nil {
 		return err
 	}
-
-	// Create services manager
-	serviceManager, err := utils.CreateServiceManager(rtDetails, false)
+	threads, err := getThreads(c)
 	if err != nil {
 		return err
 	}
+	client, err := newIndexerClient(c)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+	for _, rtReference := range rtReferences {
+		indexData, err := indexReference(ctx, serviceManager, repository, rtReference, threads, options)
+		if err != nil {
+			return err
+		}
+		indexData.SetForce(c.GetBoolFlagValue("force"))
+
+		if client == nil {
+			// Dump JSON
+			b, err := json.MarshalIndent(indexData, "", "\t")
+			if err != nil {
+				return err
+			}
+			log.Output(string(b))
+		} else if err := postIndexData(client, indexData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	log.Info("Command index-reference")
-	reference := c.Arguments[1]
-	log.Info(fmt.Sprintf(" - input reference: %s", reference))
+// newIndexerClient returns the client to call the indexer configured in the context `c`. It returns
+// nil if there is no endpoint or it is a dry-run.
+func newIndexerClient(c *components.Context) (*indexer.Client, error) {
+	endpoint := c.GetStringFlagValue("endpoint")
+	if endpoint == "" || c.GetBoolFlagValue("dry-run") {
+		return nil, nil
+	}
+	retries, err := strconv.Atoi(c.GetStringFlagValue("retries"))
+	if err != nil || retries < 0 {
+		return nil, fmt.Errorf("Invalid value for 'retries': '%s'", c.GetStringFlagValue("retries"))
+	}
+	timeout, err := strconv.Atoi(c.GetStringFlagValue("timeout"))
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("Invalid value for 'timeout': '%s'", c.GetStringFlagValue("timeout"))
+	}
 
-	// Search for the specific revision in the repository
-	rtReference, err := types.ParseStringReference(reference)
+	client := indexer.NewClient(endpoint, os.Getenv(indexerTokenEnvVar))
+	client.Retries = retries
+	client.HTTPClient.Timeout = time.Duration(timeout) * time.Second
+	return client, nil
+}
+
+// postIndexData sends `indexData` to the indexer and reports the status of the response.
+func postIndexData(client *indexer.Client, indexData *indexer.IndexData) error {
+	ref := indexData.Reference()
+	reference := ref.ToString(true)
+	response, err := client.Post(indexData)
 	if err != nil {
-		return err
+		return fmt.Errorf("Indexer call for '%s' failed: %s", reference, err)
 	}
+	log.Info(fmt.Sprintf("Indexer call for '%s': %s", reference, response.Status))
+	return nil
+}
+
+// indexReference returns the `IndexData` for the reference `rtReference` in the given `repository`. If the
+// reference has no revision, it will use the latest one (according to the `options`). If properties cannot be
+// retrieved using a single AQL query, package properties are retrieved using up to `threads` concurrent requests.
+func indexReference(ctx context.Context, serviceManager artifactory.ArtifactoryServicesManager, repository string, rtReference types.Reference, threads int, options *search.Options) (*indexer.IndexData, error) {
+	// Search for the specific revision in the repository
 	if rtReference.Revision == "" { // Search for the latest revision
-		rtRevisions, err := search.ParseRevisions(serviceManager, repository+"/"+rtReference.RtPath(false)+"/index.json")
+		revision, err := search.NewRevisionResolver(serviceManager, repository, options).ReferenceRevision(rtReference)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		rtReference.Revision = rtRevisions[len(rtRevisions)-1].Revision
+		rtReference.Revision = revision
 	}
 	log.Info(" - working reference:", rtReference.ToString(true))
 
-	// Get properties for the given reference
-	properties, err := search.ReadReferenceProperties(serviceManager, repository, *rtReference)
+	// Get properties for the given reference and all its packages
+	revisionProperties, err := readRevisionProperties(ctx, serviceManager, repository, rtReference, threads)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	indexData := indexer.NewFromProperties(*rtReference, properties)
+	properties := revisionProperties.Properties
+	indexData := indexer.NewFromProperties(rtReference, properties)
 	log.Debug(fmt.Sprintf("Reference '%s':", rtReference.ToString(true)))
 	for i := range properties {
 		prop := properties[i]
 		log.Debug(fmt.Sprintf("  %s: %s", prop.Key, prop.Value))
 	}
 
-	// Get all packages for the given reference
-	specSearchPattern := repository + "/" + rtReference.RtPath(true) + "/package/*/*/conaninfo.txt"
-	params := services.NewSearchParams()
-	params.Pattern = specSearchPattern
-	params.Recursive = false
-	params.IncludeDirs = false
-
-	reader, err := search.RunSearch(serviceManager, params)
-	if err != nil {
-		return err
-	}
-	defer reader.Close()
-
-	//
-	pkgPattern := regexp.MustCompile(rtReference.RtPath(true) + "/package/" + `(?P<pkgId>[a-z0-9]*)\/(?P<pkgRev>[a-z0-9]+)`)
-	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
-		m := pkgPattern.FindStringSubmatch(resultItem.Path)
-		pkgReference := types.Package{Ref: *rtReference, PackageId: m[1], Revision: m[2]}
-		properties, err := search.ReadPackageProperties(serviceManager, repository, pkgReference)
-		if err != nil {
-			return err
-		}
+	for _, packageProperties := range revisionProperties.Packages {
+		pkgReference := packageProperties.Package
+		properties := packageProperties.Properties
 		packageData := indexer.NewPackageUsingProperties(pkgReference, properties)
+		if !indexer.HasPackageProperties(properties) {
+			// Packages uploaded without properties, use the 'conaninfo.txt' file instead
+			log.Debug(fmt.Sprintf("Package '%s' has no properties, reading 'conaninfo.txt'", pkgReference.ToString(true)))
+			info, err := search.ReadConanInfo(serviceManager, repository, pkgReference)
+			if err != nil {
+				log.Warn(fmt.Sprintf("Cannot read 'conaninfo.txt' for package '%s': %s", pkgReference.ToString(true), err))
+			} else {
+				packageData = indexer.NewPackageUsingConanInfo(pkgReference, info)
+			}
+		}
 		indexData.Packages = append(indexData.Packages, *packageData)
 		log.Debug(fmt.Sprintf("Package '%s':", pkgReference.ToString(true)))
 		for i := range properties {
@@ -149,14 +242,5 @@ func indexReferenceCmd(c *components.Context) error {
 			log.Debug(fmt.Sprintf("  %s: %s", prop.Key, prop.Value))
 		}
 	}
-	indexData.SetForce(c.GetBoolFlagValue("force"))
-
-	// Dump JSON
-	b, err := json.MarshalIndent(indexData, "", "\t")
-	if err != nil {
-		return err
-	}
-	log.Output(string(b))
-
-	return nil
+	return indexData, nil
 }
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/search"
+)
+
+// GetIndexRepositoryCommand returns object description for the command 'index-repository'
+func GetIndexRepositoryCommand() components.Command {
+	return components.Command{
+		Name:        "index-repository",
+		Description: "Update ConanCenter indexer with properties stored in Artifactory for every reference in a repository",
+		Aliases:     []string{"ir"},
+		Arguments:   getIndexRepositoryArguments(),
+		Flags:       getIndexRepositoryFlags(),
+		EnvVars:     getIndexerEnvVars(),
+		Action: func(c *components.Context) error {
+			return indexRepositoryCmd(c)
+		},
+	}
+}
+
+func getIndexRepositoryFlags() []components.Flag {
+	return append([]components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to index (only the name). If not set, it will index all references",
+			DefaultValue: "",
+		},
+		getVersionFlag(),
+		components.BoolFlag{
+			Name:         "force",
+			Description:  "Force argument in the indexer call",
+			DefaultValue: false,
+		},
+		getThreadsFlag(),
+		getStrictFlag(),
+		getLenientFlag(),
+	}, getIndexerFlags()...)
+}
+
+func getIndexRepositoryArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+	}
+}
+
+func indexRepositoryCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command index-repository")
+	referenceName := c.GetStringFlagValue("ref-name")
+	log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+
+	client, err := newIndexerClient(c)
+	if err != nil {
+		return err
+	}
+	threads, err := getThreads(c)
+	if err != nil {
+		return err
+	}
+	versionRange, err := getVersionRange(c)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+
+	references, err := search.SearchReferences(serviceManager, repository, referenceName, true, versionRange, options)
+	if err != nil {
+		return err
+	}
+
+	// Index every reference, failures are reported at the end
+	failures := []string{}
+	for _, reference := range references {
+		indexData, err := indexReference(ctx, serviceManager, repository, reference, threads, options)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Warn(fmt.Sprintf("Cannot index reference '%s': %s", reference.ToString(true), err))
+			failures = append(failures, fmt.Sprintf("%s: %s", reference.ToString(true), err))
+			continue
+		}
+		indexData.SetForce(c.GetBoolFlagValue("force"))
+
+		if client != nil {
+			if err := postIndexData(client, indexData); err != nil {
+				log.Warn(err)
+				failures = append(failures, fmt.Sprintf("%s: %s", reference.ToString(true), err))
+			}
+			continue
+		}
+		b, err := json.Marshal(indexData)
+		if err != nil {
+			return err
+		}
+		log.Output(string(b))
+	}
+
+	log.Info(fmt.Sprintf("Indexed %d references (%d failed)", len(references)-len(failures), len(failures)))
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			log.Error(" -", failure)
+		}
+		return fmt.Errorf("Failed to index %d references", len(failures))
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/integrity"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetIntegrityCommand returns object description for the command 'integrity'
+func GetIntegrityCommand() components.Command {
+	return components.Command{
+		Name:        "integrity",
+		Description: "Check the checksums of the files against the 'conanmanifest.txt' of each revision",
+		Aliases:     []string{"int"},
+		Arguments:   getIntegrityArguments(),
+		Flags:       getIntegrityFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return integrityCmd(c)
+		},
+	}
+}
+
+func getIntegrityFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to check (only the name) if no reference is given. If not set, it will check all references",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text or json",
+			DefaultValue: string(output.Text),
+		},
+	}
+}
+
+func getIntegrityArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+		{
+			Name:        "reference",
+			Description: "[Optional] Conan reference to check (use v2 style, without trailing @). If no revision is given, it will check all of them",
+		},
+	}
+}
+
+func integrityCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 && len(c.Arguments) != 2 {
+		return errors.New("Wrong number of arguments. Expected: 1 or 2, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON)
+	if err != nil {
+		return err
+	}
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command integrity")
+	pathPattern := "*"
+	if len(c.Arguments) == 2 {
+		log.Info(fmt.Sprintf(" - input reference: %s", c.Arguments[1]))
+		rtReference, err := types.ParseStringReference(c.Arguments[1])
+		if err != nil {
+			return err
+		}
+		pathPattern = rtReference.RtPath(rtReference.Revision != "") + "/*"
+	} else {
+		referenceName := c.GetStringFlagValue("ref-name")
+		log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+		if len(referenceName) > 0 {
+			pathPattern = "*/" + referenceName + "/*"
+		}
+	}
+
+	// Checksums of the files in each revision folder
+	files, err := readFiles(serviceManager, repository, pathPattern)
+	if err != nil {
+		return err
+	}
+	folders := make(map[string]map[string]integrity.File)
+	for _, file := range files {
+		if !integrity.IsRevisionFolder(file.Path) {
+			continue
+		}
+		if _, ok := folders[file.Path]; !ok {
+			folders[file.Path] = make(map[string]integrity.File)
+		}
+		folders[file.Path][file.Name] = file
+	}
+
+	paths := []string{}
+	for path := range folders {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	issues := []integrity.Issue{}
+	verified := 0
+	for _, path := range paths {
+		var manifest *integrity.Manifest
+		if _, ok := folders[path][integrity.ManifestFile]; ok {
+			manifest, err = readManifest(serviceManager, repository+"/"+path+"/"+integrity.ManifestFile)
+			if err != nil {
+				return err
+			}
+		}
+		folderIssues, n := integrity.Check(path, manifest, folders[path])
+		issues = append(issues, folderIssues...)
+		verified += n
+	}
+
+	if format == output.Text {
+		for _, issue := range issues {
+			log.Output(issue.String())
+		}
+	} else if err := printItems(format, issues); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Checked %d revisions (%d checksums verified), found %d issues", len(paths), verified, len(issues)))
+	return nil
+}
+
+// readFiles returns the files in the `repository` whose path matches `pathPattern`, with their actual and original
+// checksums (not returned by a regular search).
+func readFiles(serviceManager artifactory.ArtifactoryServicesManager, repository string, pathPattern string) ([]integrity.File, error) {
+	query := fmt.Sprintf(`items.find({"repo":%s,"type":"file","path":{"$match":%s}}).include("path","name","actual_md5","actual_sha1","original_md5","original_sha1")`,
+		strconv.Quote(repository), strconv.Quote(pathPattern))
+	log.Debug(fmt.Sprintf("Read files using AQL '%s'", query))
+
+	stream, err := serviceManager.Aql(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	content, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Results []integrity.File `json:"results"`
+	}
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// readManifest reads and parses the 'conanmanifest.txt' file stored in `manifestPath`.
+func readManifest(serviceManager artifactory.ArtifactoryServicesManager, manifestPath string) (*integrity.Manifest, error) {
+	ioReaderCloser, err := serviceManager.ReadRemoteFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer ioReaderCloser.Close()
+	manifest, err := integrity.ParseManifest(ioReaderCloser)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse '%s': %s", manifestPath, err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/manage"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetPromoteCommand returns object description for the command 'promote'
+func GetPromoteCommand() components.Command {
+	return components.Command{
+		Name:        "promote",
+		Description: "Copy (or move) a Conan reference and its packages to another repository",
+		Aliases:     []string{"pr"},
+		Arguments:   getPromoteArguments(),
+		Flags:       getPromoteFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return promoteCmd(c)
+		},
+	}
+}
+
+func getPromoteFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.BoolFlag{
+			Name:         "all-packages",
+			Description:  "If specified, it will promote all the package revisions. Otherwise only the latest revision of each package ID",
+			DefaultValue: false,
+		},
+		components.BoolFlag{
+			Name:         "move",
+			Description:  "If specified, it will move the recipe revision instead of copying it (requires 'all-packages')",
+			DefaultValue: false,
+		},
+		components.BoolFlag{
+			Name:         "dry-run",
+			Description:  "If specified, it will only list the folders to promote",
+			DefaultValue: false,
+		},
+		getThreadsFlag(),
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getPromoteArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "src-repo",
+			Description: "Artifactory repository to promote from",
+		},
+		{
+			Name:        "dst-repo",
+			Description: "Artifactory repository to promote to",
+		},
+		{
+			Name:        "reference",
+			Description: "Conan reference to promote (use v2 style, without trailing @). If no revision is given, it will use latest one",
+		},
+	}
+}
+
+func promoteCmd(c *components.Context) error {
+	if len(c.Arguments) != 3 {
+		return errors.New("Wrong number of arguments. Expected: 3, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+	allPackages := c.GetBoolFlagValue("all-packages")
+	move := c.GetBoolFlagValue("move")
+	if move && !allPackages {
+		return errors.New("Flag 'move' requires 'all-packages'")
+	}
+	threads, err := getThreads(c)
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repositories exist and create services manager
+	source := c.Arguments[0]
+	destination := c.Arguments[1]
+	log.Info(fmt.Sprintf("Work on repositories %s -> %s", source, destination))
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), source, destination)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command promote")
+	reference := c.Arguments[2]
+	log.Info(fmt.Sprintf(" - input reference: %s", reference))
+
+	rtReference, err := types.ParseStringReference(reference)
+	if err != nil {
+		return err
+	}
+	if rtReference.Revision == "" { // Search for the latest revision
+		rtReference.Revision, err = search.NewRevisionResolver(serviceManager, source, options).ReferenceRevision(*rtReference)
+		if err != nil {
+			return err
+		}
+	}
+	log.Info(" - working reference:", rtReference.ToString(true))
+
+	packages, err := getPromotePackages(serviceManager, source, *rtReference, allPackages, options)
+	if err != nil {
+		return err
+	}
+	promotion := manage.Promotion{
+		Source:      source,
+		Destination: destination,
+		Reference:   *rtReference,
+		Packages:    packages,
+		Move:        move,
+	}
+
+	if c.GetBoolFlagValue("dry-run") {
+		for _, folder := range promotion.Folders() {
+			log.Output(fmt.Sprintf("%s/%s -> %s/%s", source, folder, destination, folder))
+		}
+		log.Info(fmt.Sprintf("Dry run: %d packages would be promoted", len(packages)))
+		return nil
+	}
+
+	// Properties are read before moving anything
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+	properties, err := readRevisionProperties(ctx, serviceManager, source, *rtReference, threads)
+	if err != nil {
+		return err
+	}
+
+	if err := manage.Promote(serviceManager, promotion, properties); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Promoted '%s' with %d packages", rtReference.ToString(true), len(packages)))
+	return nil
+}
+
+// getPromotePackages returns the packages of the reference `ref` in the `repository`: all of them or only the latest
+// revision of each package ID (according to the `options`).
+func getPromotePackages(serviceManager artifactory.ArtifactoryServicesManager, repository string, ref types.Reference, allPackages bool, options *search.Options) ([]types.Package, error) {
+	packages, err := search.SearchReferencePackages(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(types.PackagesByVersion(packages))
+	if allPackages {
+		return packages, nil
+	}
+
+	resolver := search.NewRevisionResolver(serviceManager, repository, options)
+	latest := []types.Package{}
+	for i := range packages {
+		if len(latest) > 0 && latest[len(latest)-1].PackageId == packages[i].PackageId {
+			continue
+		}
+		pkg := packages[i]
+		pkg.Revision, err = resolver.PackageRevision(pkg)
+		if err != nil {
+			return nil, err
+		}
+		latest = append(latest, pkg)
+	}
+	return latest, nil
+}
@@ -3,17 +3,12 @@ package commands
 import (
 	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
 
-	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
-	"github.com/jfrog/jfrog-cli-core/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/plugins/components"
-	"github.com/jfrog/jfrog-client-go/artifactory/services"
-	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/output"
 	"github.com/jgsogo/jcli-conan-center/search"
-	"github.com/jgsogo/jcli-conan-center/types"
 )
 
 // GetPropertiesGetCommand returns object description for the command 'properties'
@@ -43,6 +38,14 @@ func getPropertiesGetFlags() []components.Flag {
 			Description:  "If specified, it will retrieve also packages",
 			DefaultValue: false,
 		},
+		getThreadsFlag(),
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text, json or yaml",
+			DefaultValue: string(output.Text),
+		},
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
 	}
 }
 
@@ -54,7 +57,7 @@ func getPropertiesGetArguments() []components.Argument {
 		},
 		{
 			Name:        "reference",
-			Description: "Conan reference to work with (use v2 style, without trailing @). If no revision is given, it will use latest one",
+			Description: "Conan reference to work with (use v2 style, without trailing @). It accepts a revision or a selector: #latest, #latest~N, @YYYY-MM-DD or #all. If no revision is given, it will use latest one",
 		},
 	}
 }
@@ -64,26 +67,25 @@ func propertiesGetCmd(c *components.Context) error {
 		return errors.New("Wrong number of arguments. Expected: 2, " + "Received: " + strconv.Itoa(len(c.Arguments)))
 	}
 
-	rtDetails, err := commands.GetConfig(c.GetStringFlagValue("server-id"), true)
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON, output.YAML)
 	if err != nil {
-		log.Error(err)
 		return err
 	}
 
-	// Check if repository exists
-	repository := c.Arguments[0]
-	log.Output("Work on repository", repository)
-	artAuth, err := rtDetails.CreateArtAuthConfig()
-	if err != nil {
-		return err
-	}
-	err = utils.CheckIfRepoExists(repository, artAuth)
+	options, reportSkipped, err := setupSearch(c)
 	if err != nil {
 		return err
 	}
+	defer reportSkipped()
 
-	// Create services manager
-	serviceManager, err := utils.CreateServiceManager(rtDetails, false)
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	if format == output.Text {
+		log.Output("Work on repository", repository)
+	} else {
+		log.Info("Work on repository", repository)
+	}
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
 	if err != nil {
 		return err
 	}
@@ -92,60 +94,74 @@ func propertiesGetCmd(c *components.Context) error {
 	reference := c.Arguments[1]
 	log.Info(fmt.Sprintf(" - input reference: %s", reference))
 
-	// Search for the specific revision in the repository
-	rtReference, err := types.ParseStringReference(reference)
+	// Search for the specific revisions in the repository
+	rtReferences, err := selectReferences(serviceManager, repository, reference, options)
 	if err != nil {
 		return err
 	}
-	if rtReference.Revision == "" { // Search for the latest revision
-		rtRevisions, err := search.ParseRevisions(serviceManager, repository+"/"+rtReference.RtPath(false)+"/index.json")
-		if err != nil {
-			return err
-		}
-		rtReference.Revision = rtRevisions[len(rtRevisions)-1].Revision
-	}
-	log.Info(" - working reference:", rtReference.ToString(true))
-
-	// Get properties for the given reference
-	properties, err := search.ReadReferenceProperties(serviceManager, repository, *rtReference)
-	if err != nil {
-		return err
-	}
-	log.Output(fmt.Sprintf("Reference '%s':", rtReference.ToString(true)))
-	for i := range properties {
-		prop := properties[i]
-		log.Output(fmt.Sprintf("  %s: %s", prop.Key, prop.Value))
-	}
-
+	threads := defaultThreads
 	if c.GetBoolFlagValue("packages") {
-		// Get all packages for the given reference
-		specSearchPattern := repository + "/" + rtReference.RtPath(true) + "/package/*/*/conaninfo.txt"
-		params := services.NewSearchParams()
-		params.Pattern = specSearchPattern
-		params.Recursive = false
-		params.IncludeDirs = false
-
-		reader, err := search.RunSearch(serviceManager, params)
-		if err != nil {
+		if threads, err = getThreads(c); err != nil {
 			return err
 		}
-		defer reader.Close()
-
-		//
-		pkgPattern := regexp.MustCompile(rtReference.RtPath(true) + "/package/" + `(?P<pkgId>[a-z0-9]*)\/(?P<pkgRev>[a-z0-9]+)`)
-		for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
-			m := pkgPattern.FindStringSubmatch(resultItem.Path)
-			pkgReference := types.Package{Ref: *rtReference, PackageId: m[1], Revision: m[2]}
-			properties, err := search.ReadPackageProperties(serviceManager, repository, pkgReference)
+	}
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+
+	document := make(map[string]output.ReferenceProperties)
+	for i := range rtReferences {
+		rtReference := rtReferences[i]
+		log.Info(" - working reference:", rtReference.ToString(true))
+
+		// Get properties for the given reference (and all its packages)
+		var revisionProperties *search.RevisionProperties
+		if c.GetBoolFlagValue("packages") {
+			revisionProperties, err = readRevisionProperties(ctx, serviceManager, repository, rtReference, threads)
+			if err != nil {
+				return err
+			}
+		} else {
+			properties, err := search.ReadReferenceProperties(serviceManager, repository, rtReference)
 			if err != nil {
 				return err
 			}
-			log.Output(fmt.Sprintf("Package '%s':", pkgReference.ToString(true)))
+			revisionProperties = &search.RevisionProperties{Properties: properties}
+		}
+
+		properties := revisionProperties.Properties
+		referenceProperties := output.ReferenceProperties{Properties: output.NewProperties(properties)}
+		if format == output.Text {
+			log.Output(fmt.Sprintf("Reference '%s':", rtReference.ToString(true)))
 			for i := range properties {
 				prop := properties[i]
 				log.Output(fmt.Sprintf("  %s: %s", prop.Key, prop.Value))
 			}
 		}
+
+		if c.GetBoolFlagValue("packages") {
+			referenceProperties.Packages = make(map[string]output.Properties)
+			for _, packageProperties := range revisionProperties.Packages {
+				pkgReference := packageProperties.Package
+				properties := packageProperties.Properties
+				referenceProperties.Packages[pkgReference.PackageId+"#"+pkgReference.Revision] = output.NewProperties(properties)
+				if format == output.Text {
+					log.Output(fmt.Sprintf("Package '%s':", pkgReference.ToString(true)))
+					for i := range properties {
+						prop := properties[i]
+						log.Output(fmt.Sprintf("  %s: %s", prop.Key, prop.Value))
+					}
+				}
+			}
+		}
+		document[rtReference.ToString(true)] = referenceProperties
+	}
+
+	if format != output.Text {
+		str, err := output.Marshal(format, document)
+		if err != nil {
+			return err
+		}
+		log.Output(str)
 	}
 
 	return nil
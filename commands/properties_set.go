@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/manage"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// GetPropertiesSetCommand returns object description for the command 'properties-set'
+func GetPropertiesSetCommand() components.Command {
+	return components.Command{
+		Name:        "properties-set",
+		Description: "Set properties to a given Conan reference or package",
+		Aliases:     []string{"ps"},
+		Arguments:   getPropertiesSetArguments(),
+		Flags:       getPropertiesWriteFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return propertiesWriteCmd(c, false)
+		},
+	}
+}
+
+// GetPropertiesDeleteCommand returns object description for the command 'properties-delete'
+func GetPropertiesDeleteCommand() components.Command {
+	return components.Command{
+		Name:        "properties-delete",
+		Description: "Delete properties from a given Conan reference or package",
+		Aliases:     []string{"pd"},
+		Arguments:   getPropertiesDeleteArguments(),
+		Flags:       getPropertiesWriteFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return propertiesWriteCmd(c, true)
+		},
+	}
+}
+
+func getPropertiesWriteFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.BoolFlag{
+			Name:         "packages",
+			Description:  "If specified, it will work on all the packages of the reference too",
+			DefaultValue: false,
+		},
+		components.BoolFlag{
+			Name:         "recursive",
+			Description:  "If specified, it will work on all the files and folders inside the reference and packages too",
+			DefaultValue: false,
+		},
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
+	}
+}
+
+func getPropertiesWriteArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+		{
+			Name:        "reference",
+			Description: "Conan reference or package to work with (use v2 style, without trailing @). If no revision is given, it will use latest one",
+		},
+	}
+}
+
+func getPropertiesSetArguments() []components.Argument {
+	return append(getPropertiesWriteArguments(), components.Argument{
+		Name:        "properties",
+		Description: "Properties to set, with format 'key1=value1;key2=value2,value3'",
+	})
+}
+
+func getPropertiesDeleteArguments() []components.Argument {
+	return append(getPropertiesWriteArguments(), components.Argument{
+		Name:        "keys",
+		Description: "Keys of the properties to delete, with format 'key1,key2'",
+	})
+}
+
+func propertiesWriteCmd(c *components.Context, remove bool) error {
+	if len(c.Arguments) != 3 {
+		return errors.New("Wrong number of arguments. Expected: 3, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		log.Info("Command properties-delete")
+	} else {
+		log.Info("Command properties-set")
+	}
+	reference := c.Arguments[1]
+	log.Info(fmt.Sprintf(" - input reference: %s", reference))
+
+	paths, err := getPropertiesPaths(serviceManager, repository, reference, c.GetBoolFlagValue("packages"), options)
+	if err != nil {
+		return err
+	}
+
+	recursive := c.GetBoolFlagValue("recursive")
+	var modified int
+	if remove {
+		modified, err = manage.DeleteProperties(serviceManager, paths, c.Arguments[2], recursive)
+	} else {
+		modified, err = manage.SetProperties(serviceManager, paths, c.Arguments[2], recursive)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Modified properties of %d items", modified))
+	return nil
+}
+
+// getPropertiesPaths returns the Artifactory paths (including the repository) of the reference or package given in
+// `input`. Missing revisions are resolved to the latest ones (according to the `options`) and, for references,
+// `packages` adds all their packages.
+func getPropertiesPaths(serviceManager artifactory.ArtifactoryServicesManager, repository string, input string, packages bool, options *search.Options) ([]string, error) {
+	parsed, err := types.ParseString(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var rtReference *types.Reference
+	var rtPackage *types.Package
+	switch value := parsed.(type) {
+	case *types.Reference:
+		rtReference = value
+	case *types.Package:
+		rtPackage = value
+		rtReference = &rtPackage.Ref
+	}
+
+	resolver := search.NewRevisionResolver(serviceManager, repository, options)
+	if rtReference.Revision == "" { // Search for the latest revision
+		rtReference.Revision, err = resolver.ReferenceRevision(*rtReference)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rtPackage != nil {
+		if packages {
+			return nil, errors.New("Flag 'packages' cannot be used with a Conan package")
+		}
+		if rtPackage.Revision == "" { // Search for the latest package revision
+			rtPackage.Revision, err = resolver.PackageRevision(*rtPackage)
+			if err != nil {
+				return nil, err
+			}
+		}
+		log.Info(" - working package:", rtPackage.ToString(true))
+		return []string{repository + "/" + rtPackage.RtPath(true)}, nil
+	}
+
+	log.Info(" - working reference:", rtReference.ToString(true))
+	paths := []string{repository + "/" + rtReference.RtPath(true)}
+	if packages {
+		rtPackages, err := search.SearchReferencePackages(serviceManager, repository, *rtReference)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rtPackages {
+			paths = append(paths, repository+"/"+rtPackages[i].RtPath(true))
+		}
+	}
+	return paths, nil
+}
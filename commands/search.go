@@ -5,10 +5,9 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
-	"github.com/jfrog/jfrog-cli-core/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/plugins/components"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/output"
 	"github.com/jgsogo/jcli-conan-center/search"
 )
 
@@ -49,6 +48,16 @@ func getSearchFlags() []components.Flag {
 			Description:  "If specified, it will retrieve only the latest revision",
 			DefaultValue: false,
 		},
+		getVersionFlag(),
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text, json or jsonl",
+			DefaultValue: string(output.Text),
+		},
+		getStrictFlag(),
+		getLenientFlag(),
+		getLatestFallbackFlag(),
+		getRevisionSourceFlag(),
 	}
 }
 
@@ -66,26 +75,25 @@ func searchCmd(c *components.Context) error {
 		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
 	}
 
-	rtDetails, err := commands.GetConfig(c.GetStringFlagValue("server-id"), true)
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON, output.JSONLines)
 	if err != nil {
-		log.Error(err)
 		return err
 	}
-
-	// Check if repository exists
-	repository := c.Arguments[0]
-	log.Output("Work on repository", repository)
-	artAuth, err := rtDetails.CreateArtAuthConfig()
+	versionRange, err := getVersionRange(c)
 	if err != nil {
 		return err
 	}
-	err = utils.CheckIfRepoExists(repository, artAuth)
+
+	options, reportSkipped, err := setupSearch(c)
 	if err != nil {
 		return err
 	}
+	defer reportSkipped()
 
-	// Create services manager
-	serviceManager, err := utils.CreateServiceManager(rtDetails, false)
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
 	if err != nil {
 		return err
 	}
@@ -96,29 +104,38 @@ func searchCmd(c *components.Context) error {
 		referenceName := c.GetStringFlagValue("ref-name")
 		log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
 		onlyLatest := c.GetBoolFlagValue("only-latest")
-		packages, err := search.SearchPackages(serviceManager, repository, referenceName, onlyLatest, onlyLatest)
+		packages, err := search.SearchPackages(serviceManager, repository, referenceName, onlyLatest, onlyLatest, versionRange, options)
 		if err != nil {
 			return err
 		}
-		if len(packages) > 0 {
-			log.Output(fmt.Sprintf("Found %d packages:", len(packages)))
+		if format != output.Text {
+			items := []output.Package{}
 			for _, pkg := range packages {
-				log.Output(pkg.String())
+				items = append(items, output.NewPackage(repository, pkg))
 			}
+			return printItems(format, items)
+		}
+		log.Info(fmt.Sprintf("Found %d packages", len(packages)))
+		for _, pkg := range packages {
+			log.Output(pkg.String())
 		}
 	} else {
 		log.Info("Command search - retrieve recipes")
 		referenceName := c.GetStringFlagValue("ref-name")
 		log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
-		references, err := search.SearchReferences(serviceManager, repository, referenceName, c.GetBoolFlagValue("only-latest"))
+		references, err := search.SearchReferences(serviceManager, repository, referenceName, c.GetBoolFlagValue("only-latest"), versionRange, options)
 		if err != nil {
 			return err
 		}
-		if len(references) > 0 {
-			//log.Output(fmt.Sprintf("Found %d references:", len(references)))
+		if format != output.Text {
+			items := []output.Reference{}
 			for _, ref := range references {
-				log.Output(ref.String())
+				items = append(items, output.NewReference(repository, ref))
 			}
+			return printItems(format, items)
+		}
+		for _, ref := range references {
+			log.Output(ref.String())
 		}
 	}
 	return nil
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/stats"
+)
+
+// GetStatsCommand returns object description for the command 'stats'
+func GetStatsCommand() components.Command {
+	return components.Command{
+		Name:        "stats",
+		Description: "Return statistics about the contents of a repository",
+		Aliases:     []string{"st"},
+		Arguments:   getStatsArguments(),
+		Flags:       getStatsFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return statsCmd(c)
+		},
+	}
+}
+
+func getStatsFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to consider (only the name). If not set, it will consider all references",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text, json or yaml",
+			DefaultValue: string(output.Text),
+		},
+		getStrictFlag(),
+		getLenientFlag(),
+	}
+}
+
+func getStatsArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+	}
+}
+
+func statsCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON, output.YAML)
+	if err != nil {
+		return err
+	}
+
+	options, reportSkipped, err := setupSearch(c)
+	if err != nil {
+		return err
+	}
+	defer reportSkipped()
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command stats")
+	referenceName := c.GetStringFlagValue("ref-name")
+	log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+
+	collector := stats.NewCollector(repository)
+	references, err := search.SearchReferences(serviceManager, repository, referenceName, false, nil, options)
+	if err != nil {
+		return err
+	}
+	collector.AddReferences(references)
+
+	packages, err := search.SearchPackages(serviceManager, repository, referenceName, false, false, nil, options)
+	if err != nil {
+		return err
+	}
+	collector.AddPackages(packages)
+
+	if err := collectFileSizes(serviceManager, repository, referenceName, collector); err != nil {
+		return err
+	}
+
+	// Timestamps of the recipe revisions (one 'index.json' per reference)
+	visited := make(map[string]bool)
+	for i := range references {
+		ref := references[i]
+		if visited[ref.ToString(false)] {
+			continue
+		}
+		visited[ref.ToString(false)] = true
+		rtRevisions, err := search.ParseRevisions(serviceManager, repository+"/"+ref.RtPath(false)+"/index.json")
+		if err != nil {
+			log.Warn(fmt.Sprintf("Cannot read revisions of '%s': %s", ref.ToString(false), err))
+			continue
+		}
+		collector.AddRevisions(ref.Name, rtRevisions)
+	}
+
+	packagesProperties, err := search.ReadRepositoryPackagesProperties(serviceManager, repository, referenceName)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Cannot read properties of packages, settings won't be reported: %s", err))
+	}
+	for _, packageProperties := range packagesProperties {
+		collector.AddPackageProperties(packageProperties.Properties)
+	}
+
+	report := collector.Report()
+	if format != output.Text {
+		return printItems(format, report)
+	}
+
+	log.Output(fmt.Sprintf("Repository '%s':", report.Repository))
+	log.Output(fmt.Sprintf("  total: %s", formatCounts(report.Total)))
+	for _, nameCounts := range report.Names {
+		log.Output(fmt.Sprintf("  %s: %s", nameCounts.Name, formatCounts(nameCounts.Counts)))
+	}
+	log.Output("Settings:")
+	for _, key := range stats.SettingsKeys {
+		values := []string{}
+		for value := range report.Settings[key] {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for i, value := range values {
+			values[i] = fmt.Sprintf("%s (%d)", value, report.Settings[key][value])
+		}
+		log.Output(fmt.Sprintf("  %s: %s", key, strings.Join(values, ", ")))
+	}
+	return nil
+}
+
+// collectFileSizes adds to the `collector` the size of all the files matching the `referenceName` in the `repository`.
+func collectFileSizes(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, collector *stats.Collector) error {
+	params := services.NewSearchParams()
+	params.Pattern = repository + "/*"
+	if len(referenceName) > 0 {
+		params.Pattern = repository + "/*/" + referenceName + "/*"
+	}
+	params.Recursive = true
+	params.IncludeDirs = false
+
+	reader, err := search.RunSearch(serviceManager, params)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+		collector.AddFile(resultItem.Path+"/"+resultItem.Name, resultItem.Size)
+	}
+	return nil
+}
+
+func formatCounts(counts stats.Counts) string {
+	str := fmt.Sprintf("%d references, %d versions, %d recipe revisions, %d package IDs, %d package revisions, %s",
+		counts.References, counts.Versions, counts.RecipeRevisions, counts.PackageIDs, counts.PackageRevisions, formatSize(counts.Size))
+	if counts.OldestRevision != nil && counts.NewestRevision != nil {
+		str += fmt.Sprintf(" (revisions from %s to %s)", counts.OldestRevision.Format(time.RFC3339), counts.NewestRevision.Format(time.RFC3339))
+	}
+	return str
+}
+
+func formatSize(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(size)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d %s", size, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/artifactory/commands"
+	"github.com/jfrog/jfrog-cli-core/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/cache"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+const (
+	defaultThreads = 3
+)
+
+// createServiceManager returns a services manager for the Artifactory server `serverID` (or the default one if
+// empty) after checking that the given `repositories` exist.
+func createServiceManager(serverID string, repositories ...string) (artifactory.ArtifactoryServicesManager, error) {
+	rtDetails, err := commands.GetConfig(serverID, true)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	// Check if repositories exist
+	artAuth, err := rtDetails.CreateArtAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, repository := range repositories {
+		err = utils.CheckIfRepoExists(repository, artAuth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create services manager (with the on-disk cache if enabled)
+	serviceManager, err := utils.CreateServiceManager(rtDetails, false)
+	if err != nil {
+		return nil, err
+	}
+	diskCache, err := cache.FromEnv()
+	if err != nil || diskCache == nil {
+		return serviceManager, err
+	}
+	log.Debug(fmt.Sprintf("Using cache in '%s' (TTL %s)", diskCache.Dir, diskCache.TTL))
+	return cache.NewServicesManager(serviceManager, diskCache, cache.ServerKey(rtDetails.Url)), nil
+}
+
+// printItems writes the list of `items` to the standard output using the given structured `format`.
+func printItems(format output.Format, items interface{}) error {
+	str, err := output.Marshal(format, items)
+	if err != nil {
+		return err
+	}
+	if len(str) > 0 {
+		log.Output(str)
+	}
+	return nil
+}
+
+func getThreadsFlag() components.Flag {
+	return components.StringFlag{
+		Name:         "threads",
+		Description:  "Number of concurrent requests to retrieve package properties",
+		DefaultValue: strconv.Itoa(defaultThreads),
+	}
+}
+
+// getThreads returns the value of the 'threads' flag in the context `c`.
+func getThreads(c *components.Context) (int, error) {
+	threads, err := strconv.Atoi(c.GetStringFlagValue("threads"))
+	if err != nil || threads < 1 {
+		return 0, fmt.Errorf("Invalid value for 'threads': '%s'", c.GetStringFlagValue("threads"))
+	}
+	return threads, nil
+}
+
+// interruptibleContext returns a context that is cancelled when the user interrupts the command.
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			log.Warn("Interrupted, cancelling pending requests...")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
+}
+
+// readRevisionProperties returns the properties of the reference `ref` and all its packages. It uses a single AQL query
+// and, if it fails, it falls back to one request per item (up to `threads` concurrent requests).
+func readRevisionProperties(ctx context.Context, serviceManager artifactory.ArtifactoryServicesManager, repository string, ref types.Reference, threads int) (*search.RevisionProperties, error) {
+	revisionProperties, err := search.ReadRevisionProperties(serviceManager, repository, ref)
+	if err == nil {
+		return revisionProperties, nil
+	}
+	log.Warn(fmt.Sprintf("Cannot read properties using AQL (%s), falling back to one request per item", err))
+
+	properties, err := search.ReadReferenceProperties(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	revisionProperties = &search.RevisionProperties{Properties: properties}
+	packages, err := search.SearchReferencePackages(serviceManager, repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	packagesProperties, err := search.ReadPackagesProperties(ctx, serviceManager, repository, packages, threads)
+	if err != nil {
+		return nil, err
+	}
+	for i := range packages {
+		revisionProperties.Packages = append(revisionProperties.Packages, search.PackageProperties{Package: packages[i], Properties: packagesProperties[i]})
+	}
+	return revisionProperties, nil
+}
+
+func getVersionFlag() components.Flag {
+	return components.StringFlag{
+		Name:         "version",
+		Description:  "Conan version range to filter references (e.g. '[>=1.2 <2]'). If not set, all versions are considered",
+		DefaultValue: "",
+	}
+}
+
+func getStrictFlag() components.Flag {
+	return components.BoolFlag{
+		Name:         "strict",
+		Description:  "Fail on malformed entries in the repository (unexpected paths, missing 'index.json' files or revisions listed but not found). This is the default",
+		DefaultValue: false,
+	}
+}
+
+func getLenientFlag() components.Flag {
+	return components.BoolFlag{
+		Name:         "lenient",
+		Description:  "Skip malformed entries in the repository and report them instead of failing",
+		DefaultValue: false,
+	}
+}
+
+func getLatestFallbackFlag() components.Flag {
+	return components.StringFlag{
+		Name:         "latest-fallback",
+		Description:  "What to do if the latest revision listed in an 'index.json' is not found: 'newest' uses the newest revision found instead (with a warning), 'none' handles it as a malformed entry",
+		DefaultValue: string(search.FallbackNewest),
+	}
+}
+
+func getRevisionSourceFlag() components.Flag {
+	return components.StringFlag{
+		Name:         "revision-source",
+		Description:  "How the latest revision is resolved: 'index' uses the 'index.json' files, 'created' uses the time revisions were created in Artifactory",
+		DefaultValue: string(search.IndexSource),
+	}
+}
+
+// setupSearch returns the options of the search functions according to the 'strict', 'lenient', 'latest-fallback' and
+// 'revision-source' flags in the context `c` (the defaults for the flags the command doesn't have). The returned
+// function warns about the malformed entries skipped in lenient mode, call it when the command finishes.
+func setupSearch(c *components.Context) (*search.Options, func(), error) {
+	options := &search.Options{Mode: search.Strict}
+	if c.GetBoolFlagValue("strict") && c.GetBoolFlagValue("lenient") {
+		return nil, nil, errors.New("Flags 'strict' and 'lenient' cannot be used together")
+	}
+	if c.GetBoolFlagValue("lenient") {
+		options.Mode = search.Lenient
+	}
+	if value := c.GetStringFlagValue("latest-fallback"); value != "" {
+		fallback, err := search.ParseLatestFallback(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		options.Fallback = fallback
+	}
+	if value := c.GetStringFlagValue("revision-source"); value != "" {
+		source, err := search.ParseRevisionSource(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		options.Source = source
+	}
+
+	report := func() {
+		skipped := options.Skipped()
+		if len(skipped) > 0 {
+			log.Warn(fmt.Sprintf("Skipped %d malformed entries:", len(skipped)))
+			for _, err := range skipped {
+				log.Warn(fmt.Sprintf(" - %s", err))
+			}
+		}
+	}
+	return options, report, nil
+}
+
+// getVersionRange returns the version range given in the 'version' flag in the context `c` (nil if empty).
+func getVersionRange(c *components.Context) (*types.VersionRange, error) {
+	expression := c.GetStringFlagValue("version")
+	if expression == "" {
+		return nil, nil
+	}
+	return types.ParseVersionRange(expression)
+}
+
+// selectReferences returns the references chosen by the string `reference` in the given `repository`: a reference
+// with an explicit revision or followed by a revision selector (see `types.ParseReferenceSelector`).
+func selectReferences(serviceManager artifactory.ArtifactoryServicesManager, repository string, reference string, options *search.Options) ([]types.Reference, error) {
+	rtReference, selector, err := types.ParseReferenceSelector(reference)
+	if err != nil {
+		return nil, err
+	}
+	if selector == nil {
+		return []types.Reference{*rtReference}, nil
+	}
+	return search.NewRevisionResolver(serviceManager, repository, options).SelectReferenceRevisions(*rtReference, selector)
+}
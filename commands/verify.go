@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/plugins/components"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/manage"
+	"github.com/jgsogo/jcli-conan-center/output"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/jgsogo/jcli-conan-center/verify"
+)
+
+// GetVerifyCommand returns object description for the command 'verify'
+func GetVerifyCommand() components.Command {
+	return components.Command{
+		Name:        "verify",
+		Description: "Check the consistency between the folders of a repository and its 'index.json' files",
+		Aliases:     []string{"v"},
+		Arguments:   getVerifyArguments(),
+		Flags:       getVerifyFlags(),
+		EnvVars:     []components.EnvVar{},
+		Action: func(c *components.Context) error {
+			return verifyCmd(c)
+		},
+	}
+}
+
+func getVerifyFlags() []components.Flag {
+	return []components.Flag{
+		components.StringFlag{
+			Name:         "server-id",
+			Description:  "Artifactory server ID configured using the config command. If not specified, the default configured Artifactory server is used.",
+			DefaultValue: "",
+		},
+		components.StringFlag{
+			Name:         "ref-name",
+			Description:  "Name of the references to verify (only the name). If not set, it will verify all references",
+			DefaultValue: "",
+		},
+		components.BoolFlag{
+			Name:         "fix",
+			Description:  "If specified, it will rewrite the 'index.json' files to list only the existing revisions",
+			DefaultValue: false,
+		},
+		components.StringFlag{
+			Name:         "format",
+			Description:  "Output format: text or json",
+			DefaultValue: string(output.Text),
+		},
+	}
+}
+
+func getVerifyArguments() []components.Argument {
+	return []components.Argument{
+		{
+			Name:        "repo",
+			Description: "Artifactory repository name",
+		},
+	}
+}
+
+func verifyCmd(c *components.Context) error {
+	if len(c.Arguments) != 1 {
+		return errors.New("Wrong number of arguments. Expected: 1, " + "Received: " + strconv.Itoa(len(c.Arguments)))
+	}
+
+	format, err := output.ParseFormat(c.GetStringFlagValue("format"), output.Text, output.JSON)
+	if err != nil {
+		return err
+	}
+
+	// Check if repository exists and create services manager
+	repository := c.Arguments[0]
+	log.Info("Work on repository", repository)
+	serviceManager, err := createServiceManager(c.GetStringFlagValue("server-id"), repository)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Command verify")
+	referenceName := c.GetStringFlagValue("ref-name")
+	log.Info(fmt.Sprintf(" - ref-name: %s", referenceName))
+
+	// Build the tree of folders from all the files
+	params := services.NewSearchParams()
+	params.Pattern = repository + "/*"
+	if len(referenceName) > 0 {
+		params.Pattern = repository + "/*/" + referenceName + "/*"
+	}
+	params.Recursive = true
+	params.IncludeDirs = false
+	reader, err := search.RunSearch(serviceManager, params)
+	if err != nil {
+		return err
+	}
+	tree := verify.NewTree()
+	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+		modified, _ := time.Parse(time.RFC3339, resultItem.Modified)
+		tree.AddFile(resultItem.Path+"/"+resultItem.Name, modified)
+	}
+	reader.Close()
+
+	indexes := make(map[string]*types.RtIndexJSON)
+	for _, indexPath := range tree.IndexPaths() {
+		index, err := search.ReadIndexJSON(serviceManager, repository+"/"+indexPath)
+		if err != nil {
+			return err
+		}
+		indexes[indexPath] = index
+	}
+
+	issues := tree.Check(indexes)
+	if format == output.Text {
+		for _, issue := range issues {
+			log.Output(issue.String())
+		}
+	} else if err := printItems(format, issues); err != nil {
+		return err
+	}
+
+	if !c.GetBoolFlagValue("fix") {
+		log.Info(fmt.Sprintf("Found %d issues", len(issues)))
+		return nil
+	}
+	// Revisions are removed from an 'index.json' only if their folder is really gone
+	skipped := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Kind != verify.MissingFolder || skipped[issue.Path] {
+			continue
+		}
+		exists, err := folderExists(serviceManager, repository+"/"+issue.Path+"/"+issue.Revision)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Warn(fmt.Sprintf("Skip fix of '%s/index.json': the folder of revision '%s' exists", issue.Path, issue.Revision))
+			skipped[issue.Path] = true
+		}
+	}
+	fixed := make(map[string]bool)
+	for _, issue := range issues {
+		if !issue.Kind.Fixable() || fixed[issue.Path] || skipped[issue.Path] {
+			continue
+		}
+		fixed[issue.Path] = true
+		indexPath := issue.Path + "/index.json"
+		index := tree.FixIndex(issue.Path, indexes[indexPath])
+		log.Info(fmt.Sprintf("Rewrite '%s' with %d revisions", indexPath, len(index.Revisions)))
+		if err := manage.WriteIndexJSON(serviceManager, repository+"/"+indexPath, index); err != nil {
+			return err
+		}
+	}
+	log.Info(fmt.Sprintf("Found %d issues, rewritten %d 'index.json' files", len(issues), len(fixed)))
+	return nil
+}
+
+// folderExists returns true if the folder `path` (with the repository) exists in Artifactory.
+func folderExists(serviceManager artifactory.ArtifactoryServicesManager, path string) (bool, error) {
+	params := services.NewSearchParams()
+	params.Pattern = path
+	params.Recursive = false
+	params.IncludeDirs = true
+	reader, err := search.RunSearch(serviceManager, params)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+	return reader.NextRecord(new(servicesUtils.ResultItem)) == nil, nil
+}
@@ -0,0 +1,97 @@
+// Package conaninfo contains the functionality to parse the 'conaninfo.txt' file stored with each Conan package.
+package conaninfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ConanInfo contains the information from a 'conaninfo.txt' file. Values of the sections with key-value pairs are
+// stored in maps, the other sections keep the order of the lines.
+type ConanInfo struct {
+	Settings     map[string]string
+	Options      map[string]string
+	FullSettings map[string]string
+	FullOptions  map[string]string
+	Requires     []string
+	FullRequires []string
+	RecipeHash   string
+	Env          map[string]string
+}
+
+// Parse reads the contents of a 'conaninfo.txt' file from `reader`. Unknown sections are ignored.
+func Parse(reader io.Reader) (*ConanInfo, error) {
+	info := &ConanInfo{
+		Settings:     make(map[string]string),
+		Options:      make(map[string]string),
+		FullSettings: make(map[string]string),
+		FullOptions:  make(map[string]string),
+		Requires:     []string{},
+		FullRequires: []string{},
+		Env:          make(map[string]string),
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("Invalid section in line %d: '%s'", lineNumber, line)
+			}
+			section = line[1 : len(line)-1]
+			continue
+		}
+
+		var values map[string]string
+		switch section {
+		case "settings":
+			values = info.Settings
+		case "options":
+			values = info.Options
+		case "full_settings":
+			values = info.FullSettings
+		case "full_options":
+			values = info.FullOptions
+		case "env":
+			values = info.Env
+		case "requires":
+			info.Requires = append(info.Requires, line)
+			continue
+		case "full_requires":
+			info.FullRequires = append(info.FullRequires, line)
+			continue
+		case "recipe_hash":
+			info.RecipeHash = line
+			continue
+		case "":
+			return nil, fmt.Errorf("Value outside of a section in line %d: '%s'", lineNumber, line)
+		default:
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid value in section '%s', line %d: '%s'", section, lineNumber, line)
+		}
+		values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// References returns the references in the `full_requires` section, without the package IDs.
+func (info *ConanInfo) References() []string {
+	references := []string{}
+	for _, require := range info.FullRequires {
+		references = append(references, strings.SplitN(require, ":", 2)[0])
+	}
+	return references
+}
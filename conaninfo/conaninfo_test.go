@@ -0,0 +1,63 @@
+package conaninfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	wd, _ := os.Getwd()
+	file, err := os.Open(filepath.Join(wd, "testdata/conaninfo.txt"))
+	assert.Nil(t, err)
+	defer file.Close()
+
+	info, err := Parse(file)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"arch":             "x86_64",
+		"build_type":       "Release",
+		"compiler":         "gcc",
+		"compiler.libcxx":  "libstdc++11",
+		"compiler.version": "10",
+		"os":               "Linux",
+	}, info.Settings)
+	assert.Equal(t, map[string]string{"fPIC": "True", "shared": "False"}, info.Options)
+	assert.Equal(t, 8, len(info.FullSettings))
+	assert.Equal(t, "Linux", info.FullSettings["os_build"])
+	assert.Equal(t, 5, len(info.FullOptions))
+	assert.Equal(t, "False", info.FullOptions["zlib:minizip"])
+	assert.Equal(t, []string{"zlib/1.Y.Z"}, info.Requires)
+	assert.Equal(t, []string{"zlib/1.2.11:6af9cc7cb931c5ad942174fd7838eb655717c709"}, info.FullRequires)
+	assert.Equal(t, []string{"zlib/1.2.11"}, info.References())
+	assert.Equal(t, "23c789d2b5c5e7f8b4a4e7a1b5d3b4f1", info.RecipeHash)
+	assert.Equal(t, map[string]string{"CC": "gcc-10", "zlib:CFLAGS": "-O2"}, info.Env)
+}
+
+func TestParseEmpty(t *testing.T) {
+	info, err := Parse(strings.NewReader("[settings]\n\n[requires]\n\n[options]\n\n[recipe_hash]\n    hash\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(info.Settings))
+	assert.Equal(t, []string{}, info.Requires)
+	assert.Equal(t, []string{}, info.References())
+	assert.Equal(t, "hash", info.RecipeHash)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader("[settings\n"))
+	assert.Equal(t, "Invalid section in line 1: '[settings'", err.Error())
+
+	_, err = Parse(strings.NewReader("os=Linux\n"))
+	assert.Equal(t, "Value outside of a section in line 1: 'os=Linux'", err.Error())
+
+	_, err = Parse(strings.NewReader("[settings]\n    os\n"))
+	assert.Equal(t, "Invalid value in section 'settings', line 2: 'os'", err.Error())
+
+	// Unknown sections are ignored
+	info, err := Parse(strings.NewReader("[other]\n    value\n[settings]\n    os=Linux\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"os": "Linux"}, info.Settings)
+}
@@ -0,0 +1,226 @@
+// Package diff contains functionality to compare the contents of Conan repositories in Artifactory.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Change is the type of difference found for an item.
+type Change string
+
+// Kind is the type of item that differs.
+type Kind string
+
+const (
+	// Added items are only present in the right-hand side.
+	Added Change = "added"
+	// Removed items are only present in the left-hand side.
+	Removed Change = "removed"
+	// Changed items are present in both sides with different values.
+	Changed Change = "changed"
+
+	// ReferenceKind is used for references (without revision).
+	ReferenceKind Kind = "reference"
+	// RevisionKind is used for recipe revisions.
+	RevisionKind Kind = "revision"
+	// PackageKind is used for package IDs.
+	PackageKind Kind = "package"
+	// PackageRevisionKind is used for package revisions.
+	PackageRevisionKind Kind = "package-revision"
+	// PropertyKind is used for properties of references and packages.
+	PropertyKind Kind = "property"
+)
+
+// Item represents a difference between the two sides compared. For properties, `Name` is the item that contains the
+// property and `Key` is the name of the property.
+type Item struct {
+	Change Change `json:"change"`
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Key    string `json:"key,omitempty"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// String returns a human readable representation of the item, with a leading '+', '-' or '~' for added, removed
+// and changed items respectively.
+func (item Item) String() string {
+	switch item.Change {
+	case Added:
+		if item.Kind == PropertyKind {
+			return fmt.Sprintf("+ %s %s: %s=%s", item.Kind, item.Name, item.Key, item.New)
+		}
+		return fmt.Sprintf("+ %s %s", item.Kind, item.Name)
+	case Removed:
+		if item.Kind == PropertyKind {
+			return fmt.Sprintf("- %s %s: %s=%s", item.Kind, item.Name, item.Key, item.Old)
+		}
+		return fmt.Sprintf("- %s %s", item.Kind, item.Name)
+	}
+	return fmt.Sprintf("~ %s %s: %s='%s' -> '%s'", item.Kind, item.Name, item.Key, item.Old, item.New)
+}
+
+// References compares two lists of references (with revisions). References (name, version, user and channel) that
+// are only in one side are reported as a whole, for the common ones the revisions are compared.
+func References(lhs []types.Reference, rhs []types.Reference) []Item {
+	lhsRevisions := groupRevisions(lhs)
+	rhsRevisions := groupRevisions(rhs)
+
+	items := []Item{}
+	for _, name := range sortedKeys(lhsRevisions, rhsRevisions) {
+		lRevisions, lOk := lhsRevisions[name]
+		rRevisions, rOk := rhsRevisions[name]
+		switch {
+		case !rOk:
+			items = append(items, Item{Change: Removed, Kind: ReferenceKind, Name: name})
+		case !lOk:
+			items = append(items, Item{Change: Added, Kind: ReferenceKind, Name: name})
+		default:
+			items = append(items, compareSets(RevisionKind, name+"#", lRevisions, rRevisions)...)
+		}
+	}
+	return items
+}
+
+// Packages compares two lists of packages, they are matched using the package ID (so they can belong to different
+// recipe revisions). Package IDs that are only in one side are reported as a whole, for the common ones the package
+// revisions are compared. Items are named after the packages in `lhs` (or `rhs` for the added ones).
+func Packages(lhs []types.Package, rhs []types.Package) []Item {
+	lhsRevisions, lhsNames := groupPackageRevisions(lhs)
+	rhsRevisions, rhsNames := groupPackageRevisions(rhs)
+
+	items := []Item{}
+	for _, packageID := range sortedKeys(lhsRevisions, rhsRevisions) {
+		lRevisions, lOk := lhsRevisions[packageID]
+		rRevisions, rOk := rhsRevisions[packageID]
+		switch {
+		case !rOk:
+			items = append(items, Item{Change: Removed, Kind: PackageKind, Name: lhsNames[packageID]})
+		case !lOk:
+			items = append(items, Item{Change: Added, Kind: PackageKind, Name: rhsNames[packageID]})
+		default:
+			for _, item := range compareSets(PackageRevisionKind, "", lRevisions, rRevisions) {
+				if item.Change == Added {
+					item.Name = rhsNames[packageID] + "#" + item.Name
+				} else {
+					item.Name = lhsNames[packageID] + "#" + item.Name
+				}
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// Properties compares two lists of properties that belong to the item `name`. Values of multi-valued properties are
+// sorted and joined using commas before comparing them.
+func Properties(name string, lhs []servicesUtils.Property, rhs []servicesUtils.Property) []Item {
+	lhsValues := groupProperties(lhs)
+	rhsValues := groupProperties(rhs)
+
+	items := []Item{}
+	for _, key := range sortedKeys(lhsValues, rhsValues) {
+		lValue, lOk := lhsValues[key]
+		rValue, rOk := rhsValues[key]
+		switch {
+		case !rOk:
+			items = append(items, Item{Change: Removed, Kind: PropertyKind, Name: name, Key: key, Old: lValue})
+		case !lOk:
+			items = append(items, Item{Change: Added, Kind: PropertyKind, Name: name, Key: key, New: rValue})
+		case lValue != rValue:
+			items = append(items, Item{Change: Changed, Kind: PropertyKind, Name: name, Key: key, Old: lValue, New: rValue})
+		}
+	}
+	return items
+}
+
+func groupRevisions(references []types.Reference) map[string][]string {
+	revisions := make(map[string][]string)
+	for i := range references {
+		name := references[i].ToString(false)
+		revisions[name] = append(revisions[name], references[i].Revision)
+	}
+	return revisions
+}
+
+func groupPackageRevisions(packages []types.Package) (map[string][]string, map[string]string) {
+	revisions := make(map[string][]string)
+	names := make(map[string]string)
+	for i := range packages {
+		pkg := packages[i]
+		revisions[pkg.PackageId] = append(revisions[pkg.PackageId], pkg.Revision)
+		names[pkg.PackageId] = pkg.Ref.ToString(true) + ":" + pkg.PackageId
+	}
+	return revisions, names
+}
+
+func groupProperties(properties []servicesUtils.Property) map[string]string {
+	values := make(map[string][]string)
+	for _, prop := range properties {
+		values[prop.Key] = append(values[prop.Key], prop.Value)
+	}
+	joined := make(map[string]string)
+	for key, value := range values {
+		sort.Strings(value)
+		joined[key] = strings.Join(value, ",")
+	}
+	return joined
+}
+
+// compareSets returns the items added and removed between the two sets of values, their names are the values with
+// the given `prefix`.
+func compareSets(kind Kind, prefix string, lhs []string, rhs []string) []Item {
+	lhsSet := make(map[string]bool)
+	for _, value := range lhs {
+		lhsSet[value] = true
+	}
+	rhsSet := make(map[string]bool)
+	for _, value := range rhs {
+		rhsSet[value] = true
+	}
+
+	items := []Item{}
+	for _, value := range sortedKeys(lhsSet, rhsSet) {
+		if !rhsSet[value] {
+			items = append(items, Item{Change: Removed, Kind: kind, Name: prefix + value})
+		} else if !lhsSet[value] {
+			items = append(items, Item{Change: Added, Kind: kind, Name: prefix + value})
+		}
+	}
+	return items
+}
+
+// sortedKeys returns the sorted union of the keys of the given maps.
+func sortedKeys(maps ...interface{}) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, m := range maps {
+		switch typed := m.(type) {
+		case map[string][]string:
+			for key := range typed {
+				add(key)
+			}
+		case map[string]string:
+			for key := range typed {
+				add(key)
+			}
+		case map[string]bool:
+			for key := range typed {
+				add(key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferences(t *testing.T) {
+	lhs := []types.Reference{
+		types.NewReference("b2", "4.0.0", "_", "_", "rrev1"),
+		types.NewReference("b2", "4.0.0", "_", "_", "rrev2"),
+		types.NewReference("b2", "4.1.0", "_", "_", "rrev"),
+	}
+	rhs := []types.Reference{
+		types.NewReference("b2", "4.0.0", "_", "_", "rrev2"),
+		types.NewReference("b2", "4.0.0", "_", "_", "rrev3"),
+		types.NewReference("zlib", "1.2.11", "_", "_", "rrev"),
+	}
+	items := References(lhs, rhs)
+	assert.Equal(t, []Item{
+		{Change: Removed, Kind: RevisionKind, Name: "b2/4.0.0#rrev1"},
+		{Change: Added, Kind: RevisionKind, Name: "b2/4.0.0#rrev3"},
+		{Change: Removed, Kind: ReferenceKind, Name: "b2/4.1.0"},
+		{Change: Added, Kind: ReferenceKind, Name: "zlib/1.2.11"},
+	}, items)
+
+	assert.Equal(t, 0, len(References(lhs, lhs)))
+}
+
+func TestPackages(t *testing.T) {
+	lhsRef := types.NewReference("b2", "4.0.0", "_", "_", "rrev1")
+	rhsRef := types.NewReference("b2", "4.0.0", "_", "_", "rrev2")
+	lhs := []types.Package{
+		{Ref: lhsRef, PackageId: "pkg1", Revision: "prev1"},
+		{Ref: lhsRef, PackageId: "pkg2", Revision: "prev1"},
+	}
+	rhs := []types.Package{
+		{Ref: rhsRef, PackageId: "pkg1", Revision: "prev2"},
+		{Ref: rhsRef, PackageId: "pkg3", Revision: "prev1"},
+	}
+	items := Packages(lhs, rhs)
+	assert.Equal(t, []Item{
+		{Change: Removed, Kind: PackageRevisionKind, Name: "b2/4.0.0#rrev1:pkg1#prev1"},
+		{Change: Added, Kind: PackageRevisionKind, Name: "b2/4.0.0#rrev2:pkg1#prev2"},
+		{Change: Removed, Kind: PackageKind, Name: "b2/4.0.0#rrev1:pkg2"},
+		{Change: Added, Kind: PackageKind, Name: "b2/4.0.0#rrev2:pkg3"},
+	}, items)
+}
+
+func TestProperties(t *testing.T) {
+	lhs := []servicesUtils.Property{
+		{Key: "license", Value: "MIT"},
+		{Key: "topics", Value: "conan"},
+		{Key: "topics", Value: "builder"},
+		{Key: "deprecated", Value: ""},
+	}
+	rhs := []servicesUtils.Property{
+		{Key: "license", Value: "BSL-1.0"},
+		{Key: "topics", Value: "builder"},
+		{Key: "topics", Value: "conan"},
+		{Key: "homepage", Value: "https://example.com"},
+	}
+	items := Properties("b2/4.0.0#rrev", lhs, rhs)
+	assert.Equal(t, []Item{
+		{Change: Removed, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "deprecated"},
+		{Change: Added, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "homepage", New: "https://example.com"},
+		{Change: Changed, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "license", Old: "MIT", New: "BSL-1.0"},
+	}, items)
+}
+
+func TestItemString(t *testing.T) {
+	assert.Equal(t, "+ reference zlib/1.2.11", Item{Change: Added, Kind: ReferenceKind, Name: "zlib/1.2.11"}.String())
+	assert.Equal(t, "- revision b2/4.0.0#rrev1", Item{Change: Removed, Kind: RevisionKind, Name: "b2/4.0.0#rrev1"}.String())
+	assert.Equal(t, "+ property b2/4.0.0#rrev: homepage=https://example.com", Item{Change: Added, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "homepage", New: "https://example.com"}.String())
+	assert.Equal(t, "~ property b2/4.0.0#rrev: license='MIT' -> 'BSL-1.0'", Item{Change: Changed, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "license", Old: "MIT", New: "BSL-1.0"}.String())
+}
+
+func TestItemJSON(t *testing.T) {
+	// Empty values are kept, so a property set to an empty value can be told apart from a missing one
+	item := Item{Change: Changed, Kind: PropertyKind, Name: "b2/4.0.0#rrev", Key: "deprecated", New: "True"}
+	b, err := json.Marshal(item)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"change":"changed","kind":"property","name":"b2/4.0.0#rrev","key":"deprecated","old":"","new":"True"}`, string(b))
+}
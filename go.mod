@@ -7,6 +7,7 @@ require (
 	github.com/jfrog/jfrog-cli-core v1.1.2
 	github.com/jfrog/jfrog-client-go v0.16.0
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 replace github.com/jfrog/jfrog-cli-core => github.com/jfrog/jfrog-cli-core v1.1.2-0.20201118101632-f498d864d81b
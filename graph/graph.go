@@ -0,0 +1,351 @@
+// Package graph contains the functionality to build the dependency graph of a Conan reference, and to find the
+// references that depend on a given one, using the `requires` of each recipe revision.
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Recipe is a resolved reference (with revision) and the requirements declared by it.
+type Recipe struct {
+	Reference types.Reference
+	Requires  []string
+}
+
+// Resolver returns the `Recipe` for the given reference. If the reference has no revision, it should resolve
+// the latest one. Any error marks the reference as missing in the graph.
+type Resolver func(ref types.Reference) (*Recipe, error)
+
+// Node is a reference in the graph (identified by the reference without revision) with the revision it
+// resolves to and the references it requires. Missing nodes couldn't be resolved, the reason is in `Error`.
+type Node struct {
+	Reference string   `json:"reference"`
+	Revision  string   `json:"revision,omitempty"`
+	Requires  []string `json:"requires"`
+	Missing   bool     `json:"missing,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Conflict lists the different references required for the same `Name` and, for each of them, the nodes that
+// require it.
+type Conflict struct {
+	Name       string              `json:"name"`
+	References map[string][]string `json:"references"`
+}
+
+// Graph is the dependency graph of the reference `Root`. Nodes are sorted by reference and each cycle is given as
+// the list of nodes that form it, starting and ending with the same node.
+type Graph struct {
+	Root      string     `json:"root"`
+	Nodes     []Node     `json:"nodes"`
+	Conflicts []Conflict `json:"conflicts"`
+	Cycles    [][]string `json:"cycles"`
+
+	nodes map[string]*Node
+}
+
+// ParseRequirement returns the reference (without revision) for a value of the `requires` property. Values can
+// contain the null user and channel placeholders (`@_/_`) and a package ID, both are ignored.
+func ParseRequirement(value string) (*types.Reference, error) {
+	value = strings.TrimSpace(value)
+	value = strings.Replace(value, "@"+types.FilesystemPlaceHolder+"/"+types.FilesystemPlaceHolder, "", 1)
+	item, err := types.ParseString(value)
+	if err != nil {
+		return nil, err
+	}
+	var ref types.Reference
+	switch v := item.(type) {
+	case *types.Reference:
+		ref = *v
+	case *types.Package:
+		ref = v.Ref
+	}
+	ref.Revision = ""
+	ref.Timestamp = ""
+	return &ref, nil
+}
+
+// Build resolves the `root` reference and, recursively, all its requirements using `resolve`. Each reference is
+// resolved only once.
+func Build(root types.Reference, resolve Resolver) *Graph {
+	graph := &Graph{Root: root.ToString(false), nodes: make(map[string]*Node)}
+	pending := []types.Reference{root}
+	graph.nodes[graph.Root] = &Node{Reference: graph.Root, Requires: []string{}}
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		node := graph.nodes[ref.ToString(false)]
+
+		recipe, err := resolve(ref)
+		if err != nil {
+			node.Missing = true
+			node.Error = err.Error()
+			continue
+		}
+		node.Revision = recipe.Reference.Revision
+		for _, value := range recipe.Requires {
+			if value == "" {
+				continue
+			}
+			requirement, err := ParseRequirement(value)
+			if err != nil {
+				if _, ok := graph.nodes[value]; !ok {
+					graph.nodes[value] = &Node{Reference: value, Requires: []string{}, Missing: true, Error: err.Error()}
+				}
+				node.Requires = appendUnique(node.Requires, value)
+				continue
+			}
+			key := requirement.ToString(false)
+			node.Requires = appendUnique(node.Requires, key)
+			if _, ok := graph.nodes[key]; !ok {
+				graph.nodes[key] = &Node{Reference: key, Requires: []string{}}
+				pending = append(pending, *requirement)
+			}
+		}
+		sort.Strings(node.Requires)
+	}
+
+	keys := make([]string, 0, len(graph.nodes))
+	for key := range graph.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	graph.Nodes = []Node{}
+	for _, key := range keys {
+		graph.Nodes = append(graph.Nodes, *graph.nodes[key])
+	}
+	graph.Conflicts = graph.findConflicts()
+	graph.Cycles = graph.findCycles()
+	return graph
+}
+
+// Node returns the node for the given reference (without revision), or nil if it is not in the graph.
+func (graph *Graph) Node(reference string) *Node {
+	return graph.nodes[reference]
+}
+
+// Missing returns the nodes that couldn't be resolved.
+func (graph *Graph) Missing() []Node {
+	missing := []Node{}
+	for _, node := range graph.Nodes {
+		if node.Missing {
+			missing = append(missing, node)
+		}
+	}
+	return missing
+}
+
+func (graph *Graph) findConflicts() []Conflict {
+	byName := make(map[string]map[string][]string)
+	for _, node := range graph.Nodes {
+		for _, required := range node.Requires {
+			ref, err := types.ParseStringReference(required)
+			if err != nil {
+				continue
+			}
+			if _, ok := byName[ref.Name]; !ok {
+				byName[ref.Name] = make(map[string][]string)
+			}
+			byName[ref.Name][required] = append(byName[ref.Name][required], node.Reference)
+		}
+	}
+
+	conflicts := []Conflict{}
+	for _, name := range sortedKeys(byName) {
+		if len(byName[name]) > 1 {
+			conflicts = append(conflicts, Conflict{Name: name, References: byName[name]})
+		}
+	}
+	return conflicts
+}
+
+func (graph *Graph) findCycles() [][]string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	stack := []string{}
+	cycles := [][]string{}
+
+	var visit func(key string)
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, required := range graph.nodes[key].Requires {
+			switch state[required] {
+			case visiting:
+				start := 0
+				for stack[start] != required {
+					start++
+				}
+				cycle := append([]string{}, stack[start:]...)
+				cycles = append(cycles, append(cycle, required))
+			case 0:
+				visit(required)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = visited
+	}
+
+	visit(graph.Root)
+	return cycles
+}
+
+// DOT returns the graph using the Graphviz DOT language. Missing nodes are drawn in red and the references
+// involved in a conflict in orange.
+func (graph *Graph) DOT() string {
+	conflicting := make(map[string]bool)
+	for _, conflict := range graph.Conflicts {
+		for reference := range conflict.References {
+			conflicting[reference] = true
+		}
+	}
+
+	lines := []string{fmt.Sprintf("digraph %q {", graph.Root)}
+	for _, node := range graph.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", node.label())}
+		if node.Missing {
+			attrs = append(attrs, "color=red", "style=dashed")
+		} else if conflicting[node.Reference] {
+			attrs = append(attrs, "color=orange")
+		}
+		lines = append(lines, fmt.Sprintf("  %q [%s];", node.Reference, strings.Join(attrs, ", ")))
+	}
+	for _, node := range graph.Nodes {
+		for _, required := range node.Requires {
+			lines = append(lines, fmt.Sprintf("  %q -> %q;", node.Reference, required))
+		}
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+// Tree returns the lines of a text representation of the graph as a tree starting at the root. Nodes already
+// expanded are marked with '(*)' and not expanded again, requirements that close a cycle are marked with '(cycle)'.
+func (graph *Graph) Tree() []string {
+	lines := []string{graph.nodes[graph.Root].label()}
+	expanded := map[string]bool{graph.Root: true}
+	ancestors := map[string]bool{graph.Root: true}
+
+	var walk func(key string, prefix string)
+	walk = func(key string, prefix string) {
+		requires := graph.nodes[key].Requires
+		for i, required := range requires {
+			branch, indent := "├── ", "│   "
+			if i == len(requires)-1 {
+				branch, indent = "└── ", "    "
+			}
+			node := graph.nodes[required]
+			line := prefix + branch + node.label()
+			switch {
+			case ancestors[required]:
+				lines = append(lines, line+" (cycle)")
+			case expanded[required]:
+				if len(node.Requires) > 0 {
+					line += " (*)"
+				}
+				lines = append(lines, line)
+			default:
+				lines = append(lines, line)
+				expanded[required] = true
+				ancestors[required] = true
+				walk(required, prefix+indent)
+				delete(ancestors, required)
+			}
+		}
+	}
+	walk(graph.Root, "")
+	return lines
+}
+
+func (node *Node) label() string {
+	switch {
+	case node.Missing:
+		return node.Reference + " (missing)"
+	case node.Revision != "":
+		return node.Reference + "#" + node.Revision
+	}
+	return node.Reference
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func sortedKeys(m map[string]map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Dependent is a recipe revision that requires the target reference, directly (`Depth` is 1) or through other
+// dependents. `Requires` are the requirements of the recipe that lead to the target.
+type Dependent struct {
+	Reference string   `json:"reference"`
+	Depth     int      `json:"depth"`
+	Requires  []string `json:"requires"`
+}
+
+// Dependents returns the `recipes` that require, directly or transitively, any reference matching `target`: the name
+// has to be the same, the version, user and channel are compared only if they are given. Dependents are sorted by
+// depth and reference.
+func Dependents(recipes []Recipe, target types.Reference) []Dependent {
+	sameValue := func(value *string, expected *string) bool {
+		return expected == nil || (value != nil && *value == *expected)
+	}
+	matches := func(required *types.Reference) bool {
+		return required.Name == target.Name && (target.Version == "" || required.Version == target.Version) &&
+			sameValue(required.User, target.User) && sameValue(required.Channel, target.Channel)
+	}
+
+	found := make(map[string]bool)
+	dependents := []Dependent{}
+	for depth := 1; ; depth++ {
+		level := []Dependent{}
+		for i := range recipes {
+			recipe := recipes[i]
+			if found[recipe.Reference.ToString(false)] {
+				continue
+			}
+			requires := []string{}
+			for _, value := range recipe.Requires {
+				required, err := ParseRequirement(value)
+				if err != nil {
+					continue
+				}
+				key := required.ToString(false)
+				if (depth == 1 && matches(required)) || (depth > 1 && found[key]) {
+					requires = appendUnique(requires, key)
+				}
+			}
+			if len(requires) > 0 {
+				sort.Strings(requires)
+				level = append(level, Dependent{Reference: recipe.Reference.ToString(true), Depth: depth, Requires: requires})
+			}
+		}
+		if len(level) == 0 {
+			break
+		}
+		for _, dependent := range level {
+			ref, _ := types.ParseStringReference(dependent.Reference)
+			found[ref.ToString(false)] = true
+		}
+		sort.Slice(level, func(i, j int) bool { return level[i].Reference < level[j].Reference })
+		dependents = append(dependents, level...)
+	}
+	return dependents
+}
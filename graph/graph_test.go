@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResolver(requires map[string][]string) Resolver {
+	return func(ref types.Reference) (*Recipe, error) {
+		values, ok := requires[ref.ToString(false)]
+		if !ok {
+			return nil, errors.New("No revisions found")
+		}
+		ref.Revision = "rrev"
+		return &Recipe{Reference: ref, Requires: values}, nil
+	}
+}
+
+func TestParseRequirement(t *testing.T) {
+	for value, expected := range map[string]string{
+		"zlib/1.2.11":                   "zlib/1.2.11",
+		" zlib/1.2.11@_/_ ":             "zlib/1.2.11",
+		"zlib/1.2.11@user/channel#rrev": "zlib/1.2.11@user/channel",
+		"zlib/1.2.11#rrev:pkgid#prev":   "zlib/1.2.11",
+	} {
+		ref, err := ParseRequirement(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, ref.ToString(true))
+	}
+
+	_, err := ParseRequirement("zlib/[>1.2]")
+	assert.NotNil(t, err)
+}
+
+func TestBuild(t *testing.T) {
+	root, _ := types.ParseStringReference("app/1.0")
+	graph := Build(*root, newResolver(map[string][]string{
+		"app/1.0":       {"openssl/1.1.1", "zlib/1.2.11@_/_", ""},
+		"openssl/1.1.1": {"zlib/1.2.12", "missing/1.0"},
+		"zlib/1.2.11":   {},
+		"zlib/1.2.12":   {},
+	}))
+
+	assert.Equal(t, "app/1.0", graph.Root)
+	assert.Equal(t, []Node{
+		{Reference: "app/1.0", Revision: "rrev", Requires: []string{"openssl/1.1.1", "zlib/1.2.11"}},
+		{Reference: "missing/1.0", Requires: []string{}, Missing: true, Error: "No revisions found"},
+		{Reference: "openssl/1.1.1", Revision: "rrev", Requires: []string{"missing/1.0", "zlib/1.2.12"}},
+		{Reference: "zlib/1.2.11", Revision: "rrev", Requires: []string{}},
+		{Reference: "zlib/1.2.12", Revision: "rrev", Requires: []string{}},
+	}, graph.Nodes)
+	assert.Equal(t, []Conflict{
+		{Name: "zlib", References: map[string][]string{
+			"zlib/1.2.11": {"app/1.0"},
+			"zlib/1.2.12": {"openssl/1.1.1"},
+		}},
+	}, graph.Conflicts)
+	assert.Equal(t, [][]string{}, graph.Cycles)
+	assert.Equal(t, 1, len(graph.Missing()))
+	assert.Equal(t, "rrev", graph.Node("openssl/1.1.1").Revision)
+	assert.Nil(t, graph.Node("other/1.0"))
+
+	assert.Equal(t, []string{
+		"app/1.0#rrev",
+		"├── openssl/1.1.1#rrev",
+		"│   ├── missing/1.0 (missing)",
+		"│   └── zlib/1.2.12#rrev",
+		"└── zlib/1.2.11#rrev",
+	}, graph.Tree())
+}
+
+func TestBuildInvalidRequirement(t *testing.T) {
+	root, _ := types.ParseStringReference("app/1.0")
+	graph := Build(*root, newResolver(map[string][]string{"app/1.0": {"zlib/[>1.2]"}}))
+	assert.Equal(t, []string{"zlib/[>1.2]"}, graph.Node("app/1.0").Requires)
+	assert.True(t, graph.Node("zlib/[>1.2]").Missing)
+	assert.Equal(t, []Conflict{}, graph.Conflicts)
+}
+
+func TestCycles(t *testing.T) {
+	root, _ := types.ParseStringReference("liba/1.0")
+	graph := Build(*root, newResolver(map[string][]string{
+		"liba/1.0": {"libb/1.0", "libc/1.0"},
+		"libb/1.0": {"libc/1.0"},
+		"libc/1.0": {"liba/1.0"},
+	}))
+	assert.Equal(t, [][]string{
+		{"liba/1.0", "libb/1.0", "libc/1.0", "liba/1.0"},
+	}, graph.Cycles)
+	assert.Equal(t, []string{
+		"liba/1.0#rrev",
+		"├── libb/1.0#rrev",
+		"│   └── libc/1.0#rrev",
+		"│       └── liba/1.0#rrev (cycle)",
+		"└── libc/1.0#rrev (*)",
+	}, graph.Tree())
+}
+
+func TestDOT(t *testing.T) {
+	root, _ := types.ParseStringReference("app/1.0")
+	graph := Build(*root, newResolver(map[string][]string{
+		"app/1.0":       {"openssl/1.1.1", "zlib/1.2.11", "missing/1.0"},
+		"openssl/1.1.1": {"zlib/1.2.12"},
+		"zlib/1.2.11":   {},
+		"zlib/1.2.12":   {},
+	}))
+	assert.Equal(t, `digraph "app/1.0" {
+  "app/1.0" [label="app/1.0#rrev"];
+  "missing/1.0" [label="missing/1.0 (missing)", color=red, style=dashed];
+  "openssl/1.1.1" [label="openssl/1.1.1#rrev"];
+  "zlib/1.2.11" [label="zlib/1.2.11#rrev", color=orange];
+  "zlib/1.2.12" [label="zlib/1.2.12#rrev", color=orange];
+  "app/1.0" -> "missing/1.0";
+  "app/1.0" -> "openssl/1.1.1";
+  "app/1.0" -> "zlib/1.2.11";
+  "openssl/1.1.1" -> "zlib/1.2.12";
+}`, graph.DOT())
+}
+
+func TestDependents(t *testing.T) {
+	newRecipe := func(reference string, requires ...string) Recipe {
+		ref, _ := types.ParseStringReference(reference)
+		return Recipe{Reference: *ref, Requires: requires}
+	}
+	recipes := []Recipe{
+		newRecipe("zlib/1.2.11#rrev"),
+		newRecipe("openssl/1.1.1#rrev", "zlib/1.2.11"),
+		newRecipe("libcurl/7.73.0#rrev", "openssl/1.1.1", "zlib/1.2.11@_/_"),
+		newRecipe("app/1.0#rrev", "libcurl/7.73.0:pkgid"),
+		newRecipe("other/1.0#rrev", "zlib/1.2.8", "zlib/[>1.2]"),
+		newRecipe("unrelated/1.0#rrev", "bzip2/1.0.8"),
+	}
+
+	assert.Equal(t, []Dependent{
+		{Reference: "libcurl/7.73.0#rrev", Depth: 1, Requires: []string{"zlib/1.2.11"}},
+		{Reference: "openssl/1.1.1#rrev", Depth: 1, Requires: []string{"zlib/1.2.11"}},
+		{Reference: "app/1.0#rrev", Depth: 2, Requires: []string{"libcurl/7.73.0"}},
+	}, Dependents(recipes, types.Reference{Name: "zlib", Version: "1.2.11"}))
+
+	dependents := Dependents(recipes, types.Reference{Name: "zlib"})
+	assert.Equal(t, 4, len(dependents))
+	assert.Equal(t, "other/1.0#rrev", dependents[2].Reference)
+
+	assert.Equal(t, []Dependent{}, Dependents(recipes, types.Reference{Name: "app"}))
+}
+
+func TestDependentsUserChannel(t *testing.T) {
+	newRecipe := func(reference string, requires ...string) Recipe {
+		ref, _ := types.ParseStringReference(reference)
+		return Recipe{Reference: *ref, Requires: requires}
+	}
+	recipes := []Recipe{
+		newRecipe("openssl/1.1.1#rrev", "zlib/1.2.11"),
+		newRecipe("libpng/1.6.37#rrev", "zlib/1.2.11@user/stable"),
+		newRecipe("freetype/2.10.4#rrev", "zlib/1.2.11@user/testing"),
+	}
+
+	target, _ := types.ParseStringReference("zlib/1.2.11@user/stable")
+	assert.Equal(t, []Dependent{
+		{Reference: "libpng/1.6.37#rrev", Depth: 1, Requires: []string{"zlib/1.2.11@user/stable"}},
+	}, Dependents(recipes, *target))
+
+	target, _ = types.ParseStringReference("zlib/1.2.11@user")
+	assert.Equal(t, 2, len(Dependents(recipes, *target)))
+
+	assert.Equal(t, 3, len(Dependents(recipes, types.Reference{Name: "zlib", Version: "1.2.11"})))
+}
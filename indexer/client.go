@@ -0,0 +1,101 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Default values used to create a `Client`.
+const (
+	DefaultRetries    = 3
+	DefaultTimeout    = 30 * time.Second
+	DefaultRetryDelay = 2 * time.Second
+)
+
+// Client posts `IndexData` payloads to the ConanCenter indexer.
+type Client struct {
+	Endpoint   string
+	Token      string
+	Retries    int
+	RetryDelay time.Duration
+	HTTPClient *http.Client
+}
+
+// Response contains the status returned by the indexer for a payload.
+type Response struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+// NewClient creates a `Client` for the given `endpoint` with default values. The `token`, if not
+// empty, is sent as a bearer token.
+func NewClient(endpoint string, token string) *Client {
+	return &Client{
+		Endpoint:   endpoint,
+		Token:      token,
+		Retries:    DefaultRetries,
+		RetryDelay: DefaultRetryDelay,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// Post sends the `data` to the indexer endpoint. Connection errors and server errors (5xx) are retried
+// up to `Retries` times; any other status different from 2xx is returned as an error without retrying.
+func (client *Client) Post(data *IndexData) (*Response, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= client.Retries; attempt++ {
+		if attempt > 0 {
+			log.Debug(fmt.Sprintf("Retrying indexer call (%d/%d) after: %s", attempt, client.Retries, lastErr))
+			time.Sleep(client.RetryDelay)
+		}
+
+		response, err := client.post(payload)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if response.StatusCode >= 500 {
+			lastErr = fmt.Errorf("Indexer returned '%s': %s", response.Status, response.Body)
+			continue
+		}
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return response, fmt.Errorf("Indexer returned '%s': %s", response.Status, response.Body)
+		}
+		return response, nil
+	}
+	return nil, lastErr
+}
+
+func (client *Client) post(payload []byte) (*Response, error) {
+	request, err := http.NewRequest(http.MethodPost, client.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if client.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+client.Token)
+	}
+
+	resp, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}, nil
+}
@@ -0,0 +1,105 @@
+package indexer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+func TestClientPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		body, _ := ioutil.ReadAll(r.Body)
+		var data IndexData
+		assert.Nil(t, json.Unmarshal(body, &data))
+		assert.Equal(t, "name", data.Name)
+		assert.True(t, data.Force)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	defer server.Close()
+
+	data := IndexData{Name: "name", Version: "version"}
+	data.SetForce(true)
+	client := NewClient(server.URL, "token")
+	response, err := client.Post(&data)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "created", response.Body)
+}
+
+func TestClientPostRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "", r.Header.Get("Authorization"))
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	client.RetryDelay = time.Millisecond
+	response, err := client.Post(&IndexData{})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	client.Retries = 1
+	response, err = client.Post(&IndexData{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Indexer returned '503 Service Unavailable': ", err.Error())
+	assert.Equal(t, 2, calls)
+}
+
+func TestClientPostClientError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid payload"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	client.RetryDelay = time.Millisecond
+	response, err := client.Post(&IndexData{})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Indexer returned '400 Bad Request': invalid payload", err.Error())
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClientPostTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	client.Retries = 0
+	client.HTTPClient.Timeout = 10 * time.Millisecond
+	response, err := client.Post(&IndexData{})
+	assert.Nil(t, response)
+	assert.NotNil(t, err)
+}
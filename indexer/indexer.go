@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/conaninfo"
 	"github.com/jgsogo/jcli-conan-center/types"
 )
 
@@ -14,6 +15,7 @@ type Package struct {
 	Version         string            `json:"version"`
 	PackageRevision string            `json:"package_revision"`
 	Settings        map[string]string `json:"settings,omitempty"`
+	Options         map[string]string `json:"options,omitempty"`
 	Requires        []string          `json:"requires,omitempty"`
 }
 
@@ -25,6 +27,7 @@ func NewPackageUsingProperties(pkg types.Package, props []servicesUtils.Property
 		PackageRevision: pkg.Revision,
 	}
 	packageData.Settings = make(map[string]string)
+	packageData.Options = make(map[string]string)
 	for i := range props {
 		prop := props[i]
 		switch key := prop.Key; key {
@@ -33,6 +36,14 @@ func NewPackageUsingProperties(pkg types.Package, props []servicesUtils.Property
 				s := strings.SplitN(prop.Value, "=", 2)
 				packageData.AddSetting(s[0], s[1])
 			}
+		case "options":
+			if len(prop.Value) > 0 {
+				s := strings.SplitN(prop.Value, "=", 2)
+				if len(s) == 1 {
+					s = append(s, "")
+				}
+				packageData.AddOption(s[0], s[1])
+			}
 		case "requires":
 			if len(prop.Value) > 0 {
 				packageData.Requires = append(packageData.Requires, prop.Value)
@@ -42,12 +53,51 @@ func NewPackageUsingProperties(pkg types.Package, props []servicesUtils.Property
 	return packageData
 }
 
+// NewPackageUsingConanInfo creates a `Package` instance and initializes its members using the contents of the
+// 'conaninfo.txt' file of the package, to be used when the package has no properties.
+func NewPackageUsingConanInfo(pkg types.Package, info *conaninfo.ConanInfo) *Package {
+	packageData := &Package{
+		PackageID:       pkg.PackageId,
+		Version:         pkg.Ref.Version,
+		PackageRevision: pkg.Revision,
+	}
+	packageData.Settings = make(map[string]string)
+	for key, value := range info.Settings {
+		packageData.AddSetting(key, value)
+	}
+	packageData.Options = make(map[string]string)
+	for key, value := range info.Options {
+		packageData.AddOption(key, value)
+	}
+	packageData.Requires = info.References()
+	if len(packageData.Requires) == 0 {
+		packageData.Requires = nil
+	}
+	return packageData
+}
+
+// HasPackageProperties returns true if `props` contain any of the properties used to populate a `Package`.
+func HasPackageProperties(props []servicesUtils.Property) bool {
+	for i := range props {
+		if props[i].Key == "settings" || props[i].Key == "requires" {
+			return true
+		}
+	}
+	return false
+}
+
 // AddSetting add the key-value pair for a settings, taking into account some key transformations.
 func (pkg *Package) AddSetting(key string, value string) {
 	key = strings.ReplaceAll(key, ".", "_")
 	pkg.Settings[key] = value
 }
 
+// AddOption add the key-value pair for an option, the key is transformed like the ones of the settings.
+func (pkg *Package) AddOption(key string, value string) {
+	key = strings.ReplaceAll(key, ".", "_")
+	pkg.Options[key] = value
+}
+
 // IndexData is the structure with all the information.
 type IndexData struct {
 	User           string `json:"user"`
@@ -62,6 +112,8 @@ type IndexData struct {
 	URL         string `json:"giturl,omitempty"`
 	Topics      string `json:"topics,omitempty"`
 
+	Options []string `json:"options,omitempty"`
+
 	Requires []string  `json:"requires"`
 	Packages []Package `json:"packages"`
 
@@ -77,6 +129,16 @@ func (data *IndexData) SetForce(value bool) {
 	data.ForceSettings = value
 }
 
+// Reference returns the Conan reference (with user, channel and recipe revision) the data belongs to.
+func (data *IndexData) Reference() types.Reference {
+	ref := types.Reference{Name: data.Name, Version: data.Version, Revision: data.RecipeRevision}
+	if data.User != "" && data.Channel != "" {
+		user, channel := data.User, data.Channel
+		ref.User, ref.Channel = &user, &channel
+	}
+	return ref
+}
+
 // NewFromProperties creates a `IndexData` and populates it with Artifactory properties
 func NewFromProperties(ref types.Reference, props []servicesUtils.Property) *IndexData {
 	indexData := &IndexData{
@@ -105,6 +167,10 @@ func NewFromProperties(ref types.Reference, props []servicesUtils.Property) *Ind
 			indexData.URL = prop.Value
 		case "topics":
 			topics = append(topics, prop.Value)
+		case "options":
+			if len(prop.Value) > 0 && !contains(indexData.Options, prop.Value) {
+				indexData.Options = append(indexData.Options, prop.Value)
+			}
 		case "requires":
 			indexData.Requires = append(indexData.Requires, prop.Value)
 		}
@@ -112,3 +178,12 @@ func NewFromProperties(ref types.Reference, props []servicesUtils.Property) *Ind
 	indexData.Topics = strings.Join(topics, ",")
 	return indexData
 }
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,9 +2,11 @@ package indexer
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/conaninfo"
 	"github.com/jgsogo/jcli-conan-center/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -43,6 +45,38 @@ func TestNewPackageUsingProperties(t *testing.T) {
 	assert.Equal(t, []string{"name1/version", "name2/version"}, pkgData.Requires)
 }
 
+func TestNewPackageUsingConanInfo(t *testing.T) {
+	info, err := conaninfo.Parse(strings.NewReader(`[settings]
+    os=Linux
+    compiler.version=10
+[options]
+    shared=True
+[requires]
+    name1/1.Y.Z
+[full_requires]
+    name1/version:pkgID1
+    name2/version@user/channel:pkgID2
+`))
+	assert.Nil(t, err)
+
+	ref := types.Reference{Name: "name", Version: "version", Revision: "rrev"}
+	pkg := types.Package{Ref: ref, PackageId: "pkgID", Revision: "prev"}
+	pkgData := NewPackageUsingConanInfo(pkg, info)
+	assert.Equal(t, "pkgID", pkgData.PackageID)
+	assert.Equal(t, "version", pkgData.Version)
+	assert.Equal(t, "prev", pkgData.PackageRevision)
+	assert.Equal(t, map[string]string{"os": "Linux", "compiler_version": "10"}, pkgData.Settings)
+	assert.Equal(t, []string{"name1/version", "name2/version@user/channel"}, pkgData.Requires)
+	assert.Equal(t, map[string]string{"shared": "True"}, pkgData.Options)
+}
+
+func TestHasPackageProperties(t *testing.T) {
+	assert.False(t, HasPackageProperties([]servicesUtils.Property{}))
+	assert.False(t, HasPackageProperties([]servicesUtils.Property{{Key: "conan.package.name", Value: "name"}}))
+	assert.True(t, HasPackageProperties([]servicesUtils.Property{{Key: "settings", Value: "os=Linux"}}))
+	assert.True(t, HasPackageProperties([]servicesUtils.Property{{Key: "requires"}}))
+}
+
 func TestIndexData(t *testing.T) {
 	data := IndexData{}
 	data.User = "user"
@@ -115,4 +149,64 @@ func TestNewFromProperties(t *testing.T) {
 	assert.Equal(t, "MIT", indexData.License)
 	assert.Equal(t, "https://homepage.url", indexData.Homepage)
 	assert.Equal(t, "https://url.url", indexData.URL)
+	assert.Equal(t, []string{"toolset"}, indexData.Options)
+}
+
+func TestNewFromPropertiesOptions(t *testing.T) {
+	props := []servicesUtils.Property{}
+	props = append(props, servicesUtils.Property{Key: "options", Value: "shared"})
+	props = append(props, servicesUtils.Property{Key: "options"})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "fPIC"})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "shared"})
+
+	ref := types.Reference{Name: "name", Version: "version", Revision: "rrev"}
+	indexData := NewFromProperties(ref, props)
+	assert.Equal(t, []string{"shared", "fPIC"}, indexData.Options)
+
+	b, err := json.Marshal(NewFromProperties(ref, []servicesUtils.Property{{Key: "options"}}))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), `"options"`)
+}
+
+func TestNewPackageUsingPropertiesOptions(t *testing.T) {
+	props := []servicesUtils.Property{}
+	props = append(props, servicesUtils.Property{Key: "options", Value: "shared=False"})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "fPIC=True"})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "zlib.shared=True"})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "with_ssl="})
+	props = append(props, servicesUtils.Property{Key: "options", Value: "header_only"})
+	props = append(props, servicesUtils.Property{Key: "options"})
+
+	ref := types.Reference{Name: "name", Version: "version", Revision: "rrev"}
+	pkg := types.Package{Ref: ref, PackageId: "pkgID", Revision: "prev"}
+	pkgData := NewPackageUsingProperties(pkg, props)
+	assert.Equal(t, map[string]string{
+		"shared":      "False",
+		"fPIC":        "True",
+		"zlib_shared": "True",
+		"with_ssl":    "",
+		"header_only": "",
+	}, pkgData.Options)
+
+	b, err := json.Marshal(pkgData)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"package_id":"pkgID","version":"version","package_revision":"prev","options":{"fPIC":"True","header_only":"","shared":"False","with_ssl":"","zlib_shared":"True"}}`, string(b))
+
+	pkgData = NewPackageUsingProperties(pkg, []servicesUtils.Property{{Key: "options"}})
+	assert.Equal(t, 0, len(pkgData.Options))
+	b, err = json.Marshal(pkgData)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"package_id":"pkgID","version":"version","package_revision":"prev"}`, string(b))
+}
+
+func TestIndexDataReference(t *testing.T) {
+	user, channel := "user", "channel"
+	ref := types.Reference{Name: "name", Version: "version", User: &user, Channel: &channel, Revision: "rrev"}
+	indexData := NewFromProperties(ref, nil)
+	reference := indexData.Reference()
+	assert.Equal(t, "name/version@user/channel#rrev", reference.String())
+
+	indexData = NewFromProperties(types.Reference{Name: "name", Version: "version", Revision: "rrev"}, nil)
+	reference = indexData.Reference()
+	assert.Equal(t, "name/version#rrev", reference.String())
 }
@@ -0,0 +1,154 @@
+// Package integrity contains the functionality to check the files of Conan recipe and package revisions against the
+// MD5 checksums listed in their 'conanmanifest.txt' files.
+package integrity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Filenames of the files and archives stored by Conan in each revision folder.
+const (
+	ManifestFile   = "conanmanifest.txt"
+	ExportArchive  = "conan_export.tgz"
+	SourcesArchive = "conan_sources.tgz"
+	PackageArchive = "conan_package.tgz"
+)
+
+var (
+	exportFolderPattern  = regexp.MustCompile(`^[^/]+/[^/]+/[^/]+/[^/]+/[a-z0-9]+/export$`)
+	packageFolderPattern = regexp.MustCompile(`^[^/]+/[^/]+/[^/]+/[^/]+/[a-z0-9]+/package/[a-z0-9]+/[a-z0-9]+$`)
+)
+
+// Manifest contains the timestamp and the MD5 checksum of each file listed in a 'conanmanifest.txt' file.
+type Manifest struct {
+	Time  int64
+	Files map[string]string
+}
+
+// ParseManifest reads the contents of a 'conanmanifest.txt' file from `reader`: a timestamp in the first line followed
+// by one line per file with its path and MD5 checksum ('path: md5').
+func ParseManifest(reader io.Reader) (*Manifest, error) {
+	manifest := &Manifest{Files: make(map[string]string)}
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if lineNumber == 1 {
+			t, err := strconv.ParseInt(line, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid timestamp in line %d: '%s'", lineNumber, line)
+			}
+			manifest.Time = t
+			continue
+		}
+		i := strings.LastIndex(line, ": ")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid file entry in line %d: '%s'", lineNumber, line)
+		}
+		manifest.Files[line[:i]] = strings.TrimSpace(line[i+2:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// IsRevisionFolder returns true if `path` (relative to the repository) is the 'export' folder of a recipe revision
+// or the folder of a package revision.
+func IsRevisionFolder(path string) bool {
+	return exportFolderPattern.MatchString(path) || packageFolderPattern.MatchString(path)
+}
+
+// File is a file stored in Artifactory with the checksums computed from its contents (`Actual*`) and the ones given
+// by the client when it was uploaded (`Original*`), empty if none was given.
+type File struct {
+	Path         string `json:"path"`
+	Name         string `json:"name"`
+	ActualMd5    string `json:"actual_md5"`
+	ActualSha1   string `json:"actual_sha1"`
+	OriginalMd5  string `json:"original_md5"`
+	OriginalSha1 string `json:"original_sha1"`
+}
+
+// Status is the type of problem found for a file.
+type Status string
+
+const (
+	// Corrupted files have a checksum different from the one in the manifest.
+	Corrupted Status = "corrupted"
+	// Missing files are not found in the folder, nor the archive that should contain them.
+	Missing Status = "missing"
+)
+
+// Issue is a problem found with a `File` of the revision `Folder`. The checksums are given for corrupted files.
+type Issue struct {
+	Status   Status `json:"status"`
+	Folder   string `json:"folder"`
+	File     string `json:"file"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (issue Issue) String() string {
+	if issue.Status == Corrupted {
+		return fmt.Sprintf("%s: %s/%s (expected %s, found %s)", issue.Status, issue.Folder, issue.File, issue.Expected, issue.Actual)
+	}
+	return fmt.Sprintf("%s: %s/%s", issue.Status, issue.Folder, issue.File)
+}
+
+// Check compares the `manifest` of the revision `folder` (nil if there is no manifest) with the `files` found in it
+// (by name). Files listed in the manifest that are stored inside an archive can only be checked for the presence of
+// the archive. Every file, archives included, is also checked comparing its actual checksums with the original ones.
+// It returns the issues sorted by file and the number of files whose checksum was verified against the manifest.
+func Check(folder string, manifest *Manifest, files map[string]File) ([]Issue, int) {
+	issues := []Issue{}
+	corrupted := make(map[string]bool)
+	verified := 0
+	if manifest == nil {
+		issues = append(issues, Issue{Status: Missing, Folder: folder, File: ManifestFile})
+	} else {
+		archive := ExportArchive
+		if packageFolderPattern.MatchString(folder) {
+			archive = PackageArchive
+		}
+		for name, expected := range manifest.Files {
+			file, found := files[name]
+			if found {
+				verified++
+				if !strings.EqualFold(file.ActualMd5, expected) {
+					issues = append(issues, Issue{Status: Corrupted, Folder: folder, File: name, Expected: expected, Actual: file.ActualMd5})
+					corrupted[name] = true
+				}
+				continue
+			}
+			container := archive
+			if strings.HasPrefix(name, "export_source/") {
+				container = SourcesArchive
+			}
+			if _, ok := files[container]; !ok {
+				issues = append(issues, Issue{Status: Missing, Folder: folder, File: name})
+			}
+		}
+	}
+
+	for name, file := range files {
+		if corrupted[name] {
+			continue
+		}
+		if file.OriginalMd5 != "" && !strings.EqualFold(file.ActualMd5, file.OriginalMd5) {
+			issues = append(issues, Issue{Status: Corrupted, Folder: folder, File: name, Expected: file.OriginalMd5, Actual: file.ActualMd5})
+		} else if file.OriginalSha1 != "" && !strings.EqualFold(file.ActualSha1, file.OriginalSha1) {
+			issues = append(issues, Issue{Status: Corrupted, Folder: folder, File: name, Expected: file.OriginalSha1, Actual: file.ActualSha1})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].File < issues[j].File })
+	return issues, verified
+}
@@ -0,0 +1,104 @@
+package integrity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const contentManifest = `1600265105
+conanfile.py: 8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a
+conandata.yml: 0f1e2d3c4b5a69788796a5b4c3d2e1f0
+export_source/patches/0001.patch: 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d
+`
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest(strings.NewReader(contentManifest))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1600265105), manifest.Time)
+	assert.Equal(t, map[string]string{
+		"conanfile.py":                     "8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a",
+		"conandata.yml":                    "0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+		"export_source/patches/0001.patch": "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d",
+	}, manifest.Files)
+
+	_, err = ParseManifest(strings.NewReader("conanfile.py: 8a5f"))
+	assert.Equal(t, "Invalid timestamp in line 1: 'conanfile.py: 8a5f'", err.Error())
+
+	_, err = ParseManifest(strings.NewReader("1600265105\nconanfile.py"))
+	assert.Equal(t, "Invalid file entry in line 2: 'conanfile.py'", err.Error())
+}
+
+func TestIsRevisionFolder(t *testing.T) {
+	assert.True(t, IsRevisionFolder("_/b2/4.0.0/_/rrev/export"))
+	assert.True(t, IsRevisionFolder("_/b2/4.0.0/_/rrev/package/pkgid/prev"))
+	assert.False(t, IsRevisionFolder("_/b2/4.0.0/_/rrev"))
+	assert.False(t, IsRevisionFolder("_/b2/4.0.0/_/rrev/export_source"))
+	assert.False(t, IsRevisionFolder("_/b2/4.0.0/_/rrev/package/pkgid"))
+}
+
+func TestCheckExport(t *testing.T) {
+	manifest, _ := ParseManifest(strings.NewReader(contentManifest))
+	folder := "_/b2/4.0.0/_/rrev/export"
+
+	issues, verified := Check(folder, manifest, map[string]File{
+		"conanfile.py": {ActualMd5: "8A5F8E4A3BDC1A8B3A7C4C2F5E2D1B0A", OriginalMd5: "8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a"},
+		ManifestFile:   {ActualMd5: "md5"},
+		ExportArchive:  {ActualMd5: "md5", OriginalMd5: "md5"},
+		SourcesArchive: {ActualMd5: "md5"},
+	})
+	assert.Equal(t, 0, len(issues))
+	assert.Equal(t, 1, verified)
+
+	issues, verified = Check(folder, manifest, map[string]File{
+		"conanfile.py": {ActualMd5: "other", OriginalMd5: "8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a"},
+		ManifestFile:   {ActualMd5: "md5"},
+	})
+	assert.Equal(t, []Issue{
+		{Status: Missing, Folder: folder, File: "conandata.yml"},
+		{Status: Corrupted, Folder: folder, File: "conanfile.py", Expected: "8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a", Actual: "other"},
+		{Status: Missing, Folder: folder, File: "export_source/patches/0001.patch"},
+	}, issues)
+	assert.Equal(t, 1, verified)
+	assert.Equal(t, "corrupted: _/b2/4.0.0/_/rrev/export/conanfile.py (expected 8a5f8e4a3bdc1a8b3a7c4c2f5e2d1b0a, found other)", issues[1].String())
+	assert.Equal(t, "missing: _/b2/4.0.0/_/rrev/export/conandata.yml", issues[0].String())
+}
+
+func TestCheckPackage(t *testing.T) {
+	manifest, _ := ParseManifest(strings.NewReader("1600265105\nconaninfo.txt: aaaa\ninclude/zlib.h: bbbb\n"))
+	folder := "_/zlib/1.2.11/_/rrev/package/pkgid/prev"
+
+	issues, verified := Check(folder, manifest, map[string]File{"conaninfo.txt": {ActualMd5: "aaaa"}, PackageArchive: {ActualMd5: "md5"}})
+	assert.Equal(t, 0, len(issues))
+	assert.Equal(t, 1, verified)
+
+	issues, _ = Check(folder, manifest, map[string]File{"conaninfo.txt": {ActualMd5: "aaaa"}, ExportArchive: {ActualMd5: "md5"}})
+	assert.Equal(t, []Issue{{Status: Missing, Folder: folder, File: "include/zlib.h"}}, issues)
+
+	issues, verified = Check(folder, nil, map[string]File{"conaninfo.txt": {ActualMd5: "aaaa"}})
+	assert.Equal(t, []Issue{{Status: Missing, Folder: folder, File: ManifestFile}}, issues)
+	assert.Equal(t, 0, verified)
+}
+
+func TestCheckArchives(t *testing.T) {
+	manifest, _ := ParseManifest(strings.NewReader("1600265105\nconaninfo.txt: aaaa\ninclude/zlib.h: bbbb\n"))
+	folder := "_/zlib/1.2.11/_/rrev/package/pkgid/prev"
+
+	issues, verified := Check(folder, manifest, map[string]File{
+		"conaninfo.txt": {ActualMd5: "aaaa", ActualSha1: "sha1", OriginalMd5: "aaaa", OriginalSha1: "other"},
+		PackageArchive:  {ActualMd5: "md5", ActualSha1: "sha1", OriginalMd5: "original", OriginalSha1: "sha1"},
+		ManifestFile:    {ActualMd5: "md5", ActualSha1: "sha1"},
+	})
+	assert.Equal(t, []Issue{
+		{Status: Corrupted, Folder: folder, File: PackageArchive, Expected: "original", Actual: "md5"},
+		{Status: Corrupted, Folder: folder, File: "conaninfo.txt", Expected: "other", Actual: "sha1"},
+	}, issues)
+	assert.Equal(t, 1, verified)
+
+	issues, _ = Check(folder, nil, map[string]File{PackageArchive: {ActualMd5: "md5", OriginalMd5: "original"}})
+	assert.Equal(t, []Issue{
+		{Status: Corrupted, Folder: folder, File: PackageArchive, Expected: "original", Actual: "md5"},
+		{Status: Missing, Folder: folder, File: ManifestFile},
+	}, issues)
+}
@@ -28,6 +28,18 @@ func getCommands() []components.Command {
 	return []components.Command{
 		commands.GetSearchCommand(),
 		commands.GetPropertiesGetCommand(),
+		commands.GetPropertiesSetCommand(),
+		commands.GetPropertiesDeleteCommand(),
 		commands.GetIndexReferenceCommand(),
+		commands.GetIndexRepositoryCommand(),
+		commands.GetCleanupCommand(),
+		commands.GetPromoteCommand(),
+		commands.GetDiffCommand(),
+		commands.GetStatsCommand(),
+		commands.GetCacheCommand(),
+		commands.GetGraphCommand(),
+		commands.GetDependentsCommand(),
+		commands.GetVerifyCommand(),
+		commands.GetIntegrityCommand(),
 	}
 }
@@ -0,0 +1,83 @@
+package manage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// RetentionPolicy defines which revisions are stale: the ones that are not among the `Keep` latest ones (if
+// greater than zero) and the ones created before the `Before` date (if not zero). The latest revision is never
+// considered stale.
+type RetentionPolicy struct {
+	Keep   int
+	Before time.Time
+}
+
+// StaleRevisions returns the `revisions` that are stale according to the policy, sorted by time.
+func (policy RetentionPolicy) StaleRevisions(revisions []types.RtRevisionsData) []types.RtRevisionsData {
+	sorted := append([]types.RtRevisionsData{}, revisions...)
+	sort.Sort(types.ByTime(sorted))
+
+	stale := []types.RtRevisionsData{}
+	for i, revision := range sorted {
+		position := len(sorted) - i // Latest revision is at position 1
+		if position == 1 {
+			break
+		}
+		if (policy.Keep > 0 && position > policy.Keep) || (!policy.Before.IsZero() && revision.Time.Before(policy.Before)) {
+			stale = append(stale, revision)
+		}
+	}
+	return stale
+}
+
+// StaleFoundRevisions returns the stale revisions among the ones found in the repository: `revisions` as returned by
+// `search.RevisionResolver.ReferenceRevisions` or `PackageRevisions`, sorted by time with the latest one last.
+// Revisions listed in the 'index.json' file without a folder are not among them, so they don't count for `Keep`, and
+// the latest revision is never stale, even if a revision not listed was created after it.
+func (policy RetentionPolicy) StaleFoundRevisions(revisions []types.RtRevisionsData) []types.RtRevisionsData {
+	if len(revisions) == 0 {
+		return nil
+	}
+	latest := revisions[len(revisions)-1].Revision
+	stale := []types.RtRevisionsData{}
+	for _, revision := range policy.StaleRevisions(revisions) {
+		if revision.Revision != latest {
+			stale = append(stale, revision)
+		}
+	}
+	return stale
+}
+
+// RemoveRevisions deletes the folders of the given `revisions` found in `basePath` (it includes the repository) and
+// removes them from the 'index.json' file in the same path. If any deletion fails, the 'index.json' file is updated
+// with the revisions deleted so far and the error is returned.
+func RemoveRevisions(serviceManager artifactory.ArtifactoryServicesManager, basePath string, revisions []types.RtRevisionsData) error {
+	index, err := search.ReadIndexJSON(serviceManager, basePath+"/index.json")
+	if err != nil {
+		return err
+	}
+
+	deleted := []types.RtRevisionsData{}
+	var deleteErr error
+	for _, revision := range revisions {
+		if deleteErr = DeleteFolder(serviceManager, basePath+"/"+revision.Revision); deleteErr != nil {
+			deleteErr = fmt.Errorf("Cannot delete revision '%s' in '%s': %s", revision.Revision, basePath, deleteErr)
+			break
+		}
+		deleted = append(deleted, revision)
+	}
+
+	if len(deleted) > 0 {
+		index.RemoveRevisions(deleted)
+		if err := WriteIndexJSON(serviceManager, basePath+"/index.json", index); err != nil {
+			return err
+		}
+	}
+	return deleteErr
+}
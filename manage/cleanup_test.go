@@ -0,0 +1,109 @@
+package manage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseRevisions(t *testing.T) []types.RtRevisionsData {
+	var index types.RtIndexJSON
+	assert.Nil(t, json.Unmarshal([]byte(contentRevisions), &index))
+	return index.Revisions
+}
+
+func TestStaleRevisions(t *testing.T) {
+	revisions := parseRevisions(t)
+
+	stale := RetentionPolicy{}.StaleRevisions(revisions)
+	assert.Equal(t, 0, len(stale))
+
+	stale = RetentionPolicy{Keep: 1}.StaleRevisions(revisions)
+	assert.Equal(t, 2, len(stale))
+	assert.Equal(t, "rrev1", stale[0].Revision)
+	assert.Equal(t, "rrev2", stale[1].Revision)
+
+	stale = RetentionPolicy{Keep: 2}.StaleRevisions(revisions)
+	assert.Equal(t, 1, len(stale))
+	assert.Equal(t, "rrev1", stale[0].Revision)
+
+	stale = RetentionPolicy{Keep: 5}.StaleRevisions(revisions)
+	assert.Equal(t, 0, len(stale))
+
+	stale = RetentionPolicy{Before: time.Date(2020, 8, 16, 0, 0, 0, 0, time.UTC)}.StaleRevisions(revisions)
+	assert.Equal(t, 1, len(stale))
+	assert.Equal(t, "rrev1", stale[0].Revision)
+
+	// The latest revision is never stale
+	stale = RetentionPolicy{Before: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}.StaleRevisions(revisions)
+	assert.Equal(t, 2, len(stale))
+
+	stale = RetentionPolicy{Keep: 2, Before: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)}.StaleRevisions(revisions)
+	assert.Equal(t, 2, len(stale))
+}
+
+func TestStaleFoundRevisions(t *testing.T) {
+	// 'rrev3' is the latest revision listed in the 'index.json' but its folder is missing
+	servicesManager := MockArtifactoryServicesManager{results: map[string]string{
+		"repo/_/b2/4.0.0/_/*/export/conanfile.py": `{"results": [
+			{"repo": "repo", "path": "_/b2/4.0.0/_/rrev2/export", "name": "conanfile.py", "type": "file"},
+			{"repo": "repo", "path": "_/b2/4.0.0/_/rrev1/export", "name": "conanfile.py", "type": "file"}
+		]}`,
+	}}
+	ref := types.NewReference("b2", "4.0.0", "_", "_", "")
+	revisions, err := search.NewRevisionResolver(&servicesManager, "repo", nil).ReferenceRevisions(ref)
+	assert.Nil(t, err)
+
+	// The newest revision found is kept, 'rrev3' doesn't count
+	stale := RetentionPolicy{Keep: 1}.StaleFoundRevisions(revisions)
+	assert.Equal(t, 1, len(stale))
+	assert.Equal(t, "rrev1", stale[0].Revision)
+	stale = RetentionPolicy{Before: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}.StaleFoundRevisions(revisions)
+	assert.Equal(t, 1, len(stale))
+	assert.Equal(t, "rrev1", stale[0].Revision)
+
+	// Nothing is removed if the latest revision cannot be resolved
+	_, err = search.NewRevisionResolver(&servicesManager, "repo", &search.Options{Fallback: search.FallbackNone}).ReferenceRevisions(ref)
+	assert.NotNil(t, err)
+
+	// The latest revision is never stale, even if a revision not listed was created after it
+	revisions = []types.RtRevisionsData{
+		{Revision: "rrev4", Time: types.RtTimestamp{Time: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)}},
+		{Revision: "rrev3", Time: types.RtTimestamp{Time: time.Date(2020, 9, 16, 0, 0, 0, 0, time.UTC)}},
+	}
+	assert.Equal(t, 0, len(RetentionPolicy{Keep: 1}.StaleFoundRevisions(revisions)))
+}
+
+func TestRemoveRevisions(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	stale := RetentionPolicy{Keep: 1}.StaleRevisions(parseRevisions(t))
+	err := RemoveRevisions(&servicesManager, "repo/_/b2/4.0.0/_", stale)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"repo/_/b2/4.0.0/_/rrev1/", "repo/_/b2/4.0.0/_/rrev2/"}, servicesManager.deleted)
+
+	var index types.RtIndexJSON
+	assert.Nil(t, json.Unmarshal([]byte(servicesManager.uploaded["repo/_/b2/4.0.0/_/index.json"]), &index))
+	assert.Equal(t, "b2/4.0.0@_/_", index.Reference)
+	assert.Equal(t, 1, len(index.Revisions))
+	assert.Equal(t, "rrev3", index.Revisions[0].Revision)
+}
+
+func TestRemoveRevisionsError(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{failOn: "repo/_/b2/4.0.0/_/rrev2/"}
+	stale := RetentionPolicy{Keep: 1}.StaleRevisions(parseRevisions(t))
+	err := RemoveRevisions(&servicesManager, "repo/_/b2/4.0.0/_", stale)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Cannot delete revision 'rrev2' in 'repo/_/b2/4.0.0/_': cannot delete", err.Error())
+	assert.Equal(t, []string{"repo/_/b2/4.0.0/_/rrev1/"}, servicesManager.deleted)
+
+	// Index is updated with the revisions already deleted
+	var index types.RtIndexJSON
+	assert.Nil(t, json.Unmarshal([]byte(servicesManager.uploaded["repo/_/b2/4.0.0/_/index.json"]), &index))
+	assert.Equal(t, 2, len(index.Revisions))
+	assert.Equal(t, "rrev3", index.Revisions[0].Revision)
+	assert.Equal(t, "rrev2", index.Revisions[1].Revision)
+}
@@ -0,0 +1,62 @@
+// Package manage contains functionality to modify the contents of a Conan repository in Artifactory.
+package manage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// WriteIndexJSON uploads the `index` to the given `indexPath` (it includes the repository), replacing any existing file.
+func WriteIndexJSON(serviceManager artifactory.ArtifactoryServicesManager, indexPath string, index *types.RtIndexJSON) error {
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile, err := ioutil.TempFile("", "index-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(b)
+	tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	params := services.NewUploadParams()
+	params.Pattern = tmpFile.Name()
+	params.Target = indexPath
+	params.Flat = true
+	params.Recursive = false
+	log.Debug(fmt.Sprintf("Upload '%s'", indexPath))
+	uploaded, failed, err := serviceManager.UploadFiles(params)
+	if err != nil {
+		return err
+	}
+	if failed > 0 || uploaded != 1 {
+		return fmt.Errorf("Failed to upload '%s'", indexPath)
+	}
+	return nil
+}
+
+// DeleteFolder deletes the folder `path` (it includes the repository) and all its contents.
+func DeleteFolder(serviceManager artifactory.ArtifactoryServicesManager, path string) error {
+	params := services.NewDeleteParams()
+	params.Pattern = path + "/"
+	params.Recursive = true
+	log.Debug(fmt.Sprintf("Delete folder '%s'", path))
+	reader, err := serviceManager.GetPathsToDelete(params)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = serviceManager.DeleteFiles(reader)
+	return err
+}
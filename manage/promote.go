@@ -0,0 +1,175 @@
+package manage
+
+import (
+	"fmt"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Promotion describes the copy (or move) of a recipe revision and some of its packages from the repository `Source`
+// to the repository `Destination`. The `Reference` and the `Packages` must contain the revisions.
+type Promotion struct {
+	Source      string
+	Destination string
+	Reference   types.Reference
+	Packages    []types.Package
+	Move        bool
+}
+
+// Folders returns the folders (relative to the repository) whose contents are transferred by the promotion.
+func (promotion Promotion) Folders() []string {
+	folders := []string{promotion.Reference.RtPath(true) + "/export"}
+	for i := range promotion.Packages {
+		folders = append(folders, promotion.Packages[i].RtPath(true))
+	}
+	return folders
+}
+
+// Promote transfers the files of the recipe revision and packages, adds the revisions to the 'index.json' files in
+// the destination repository and sets the given `properties` (read from the source) to the destination folders. When
+// moving, the recipe revision is removed from the source repository once everything has been transferred.
+func Promote(serviceManager artifactory.ArtifactoryServicesManager, promotion Promotion, properties *search.RevisionProperties) error {
+	ref := promotion.Reference
+	for _, folder := range promotion.Folders() {
+		if err := transferFolder(serviceManager, promotion, folder); err != nil {
+			return err
+		}
+	}
+
+	// Merge revisions into the destination 'index.json' files
+	if err := mergeIndexJSON(serviceManager, promotion, ref.RtPath(false), ref.Revision); err != nil {
+		return err
+	}
+	for i := range promotion.Packages {
+		pkg := promotion.Packages[i]
+		if err := mergeIndexJSON(serviceManager, promotion, pkg.RtPath(false), pkg.Revision); err != nil {
+			return err
+		}
+	}
+
+	// Carry properties across
+	if properties != nil {
+		if err := setFolderProperties(serviceManager, promotion.Destination+"/"+ref.RtPath(true), PropertiesString(properties.Properties)); err != nil {
+			return err
+		}
+		for _, packageProperties := range properties.Packages {
+			pkg := packageProperties.Package
+			if !containsPackage(promotion.Packages, pkg) {
+				continue
+			}
+			if err := setFolderProperties(serviceManager, promotion.Destination+"/"+pkg.RtPath(true), PropertiesString(packageProperties.Properties)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if promotion.Move {
+		sourceIndex, err := search.ReadIndexJSON(serviceManager, promotion.Source+"/"+ref.RtPath(false)+"/index.json")
+		if err != nil {
+			return err
+		}
+		revision, err := findRevision(sourceIndex, ref.Revision, promotion.Source+"/"+ref.RtPath(false)+"/index.json")
+		if err != nil {
+			return err
+		}
+		return RemoveRevisions(serviceManager, promotion.Source+"/"+ref.RtPath(false), []types.RtRevisionsData{*revision})
+	}
+	return nil
+}
+
+// transferFolder copies (or moves) the contents of `folder` (relative to the repository) keeping the same path.
+func transferFolder(serviceManager artifactory.ArtifactoryServicesManager, promotion Promotion, folder string) error {
+	params := services.NewMoveCopyParams()
+	params.Pattern = promotion.Source + "/" + folder + "/"
+	params.Target = promotion.Destination + "/"
+	params.Recursive = true
+	params.Flat = false
+
+	var failed int
+	var err error
+	if promotion.Move {
+		log.Debug(fmt.Sprintf("Move '%s' to '%s'", params.Pattern, params.Target))
+		_, failed, err = serviceManager.Move(params)
+	} else {
+		log.Debug(fmt.Sprintf("Copy '%s' to '%s'", params.Pattern, params.Target))
+		_, failed, err = serviceManager.Copy(params)
+	}
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%d items failed", failed)
+	}
+	if err != nil {
+		return fmt.Errorf("Cannot transfer '%s' to '%s': %s", params.Pattern, promotion.Destination, err)
+	}
+	return nil
+}
+
+// mergeIndexJSON adds the `revision` listed in the source 'index.json' found in `basePath` (relative to the
+// repository) to the destination one, creating it if it doesn't exist.
+func mergeIndexJSON(serviceManager artifactory.ArtifactoryServicesManager, promotion Promotion, basePath string, revision string) error {
+	indexPath := basePath + "/index.json"
+	sourceIndex, err := search.ReadIndexJSON(serviceManager, promotion.Source+"/"+indexPath)
+	if err != nil {
+		return err
+	}
+	sourceRevision, err := findRevision(sourceIndex, revision, promotion.Source+"/"+indexPath)
+	if err != nil {
+		return err
+	}
+
+	exists, err := fileExists(serviceManager, promotion.Destination+"/"+indexPath)
+	if err != nil {
+		return err
+	}
+	index := &types.RtIndexJSON{Reference: sourceIndex.Reference, PackageReference: sourceIndex.PackageReference}
+	if exists {
+		index, err = search.ReadIndexJSON(serviceManager, promotion.Destination+"/"+indexPath)
+		if err != nil {
+			return err
+		}
+	}
+	index.AddRevisions([]types.RtRevisionsData{*sourceRevision})
+	return WriteIndexJSON(serviceManager, promotion.Destination+"/"+indexPath, index)
+}
+
+func findRevision(index *types.RtIndexJSON, revision string, indexPath string) (*types.RtRevisionsData, error) {
+	for i := range index.Revisions {
+		if index.Revisions[i].Revision == revision {
+			return &index.Revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Revision '%s' not found in '%s'", revision, indexPath)
+}
+
+func fileExists(serviceManager artifactory.ArtifactoryServicesManager, path string) (bool, error) {
+	params := services.NewSearchParams()
+	params.Pattern = path
+	params.Recursive = false
+	reader, err := serviceManager.SearchFiles(params)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+	length, err := reader.Length()
+	return length > 0, err
+}
+
+func setFolderProperties(serviceManager artifactory.ArtifactoryServicesManager, path string, props string) error {
+	if props == "" {
+		return nil
+	}
+	_, err := SetProperties(serviceManager, []string{path}, props, false)
+	return err
+}
+
+func containsPackage(packages []types.Package, pkg types.Package) bool {
+	for _, p := range packages {
+		if p.PackageId == pkg.PackageId && p.Revision == pkg.Revision {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package manage
+
+import (
+	"encoding/json"
+	"testing"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/search"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	contentPackageRevisions = `{
+		"packageReference": "b2/4.0.0@_/_#rrev2:pkgID",
+		"revisions": [{
+			"revision": "prev1",
+			"time": "2020-08-17T15:25:47.871+0000"
+		}]
+	}`
+	contentDestinationRevisions = `{
+		"reference": "b2/4.0.0@_/_",
+		"revisions": [{
+			"revision": "rrev3",
+			"time": "2020-09-16T14:05:05.965+0000"
+		}]
+	}`
+)
+
+func newPromotion(move bool) Promotion {
+	ref := types.NewReference("b2", "4.0.0", "_", "_", "rrev2")
+	return Promotion{
+		Source:      "src",
+		Destination: "dst",
+		Reference:   ref,
+		Packages:    []types.Package{{Ref: ref, PackageId: "pkgID", Revision: "prev1"}},
+		Move:        move,
+	}
+}
+
+func newPromoteServicesManager() MockArtifactoryServicesManager {
+	return MockArtifactoryServicesManager{files: map[string]string{
+		"src/_/b2/4.0.0/_/rrev2/package/pkgID/index.json": contentPackageRevisions,
+		"dst/_/b2/4.0.0/_/index.json":                     contentDestinationRevisions,
+	}}
+}
+
+func TestPromoteCopy(t *testing.T) {
+	servicesManager := newPromoteServicesManager()
+	promotion := newPromotion(false)
+	ref := promotion.Reference
+	properties := &search.RevisionProperties{
+		Properties: []servicesUtils.Property{{Key: "license", Value: "MIT"}},
+		Packages: []search.PackageProperties{
+			{Package: types.Package{Ref: ref, PackageId: "pkgID", Revision: "prev1"}, Properties: []servicesUtils.Property{{Key: "os", Value: "Linux"}}},
+			{Package: types.Package{Ref: ref, PackageId: "other", Revision: "prev"}, Properties: []servicesUtils.Property{{Key: "os", Value: "Windows"}}},
+		},
+	}
+	err := Promote(&servicesManager, promotion, properties)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"copy src/_/b2/4.0.0/_/rrev2/export/ dst/",
+		"copy src/_/b2/4.0.0/_/rrev2/package/pkgID/prev1/ dst/",
+	}, servicesManager.transferred)
+	assert.Nil(t, servicesManager.deleted)
+
+	// Revision is merged into the existing index
+	var index types.RtIndexJSON
+	assert.Nil(t, json.Unmarshal([]byte(servicesManager.uploaded["dst/_/b2/4.0.0/_/index.json"]), &index))
+	assert.Equal(t, "b2/4.0.0@_/_", index.Reference)
+	assert.Equal(t, 2, len(index.Revisions))
+	assert.Equal(t, "rrev3", index.Revisions[0].Revision)
+	assert.Equal(t, "rrev2", index.Revisions[1].Revision)
+
+	// Package index is created
+	index = types.RtIndexJSON{}
+	assert.Nil(t, json.Unmarshal([]byte(servicesManager.uploaded["dst/_/b2/4.0.0/_/rrev2/package/pkgID/index.json"]), &index))
+	assert.Equal(t, "b2/4.0.0@_/_#rrev2:pkgID", index.PackageReference)
+	assert.Equal(t, 1, len(index.Revisions))
+	assert.Equal(t, "prev1", index.Revisions[0].Revision)
+
+	// Last properties set are the ones of the package
+	assert.Equal(t, "os=Linux", servicesManager.props)
+	assert.Equal(t, []string{"dst/_/b2/4.0.0/_/rrev2/package/pkgID/prev1/"}, servicesManager.items)
+}
+
+func TestPromoteMove(t *testing.T) {
+	servicesManager := newPromoteServicesManager()
+	err := Promote(&servicesManager, newPromotion(true), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"move src/_/b2/4.0.0/_/rrev2/export/ dst/",
+		"move src/_/b2/4.0.0/_/rrev2/package/pkgID/prev1/ dst/",
+	}, servicesManager.transferred)
+	assert.Equal(t, []string{"src/_/b2/4.0.0/_/rrev2/"}, servicesManager.deleted)
+
+	var index types.RtIndexJSON
+	assert.Nil(t, json.Unmarshal([]byte(servicesManager.uploaded["src/_/b2/4.0.0/_/index.json"]), &index))
+	assert.Equal(t, 2, len(index.Revisions))
+	assert.Equal(t, "rrev3", index.Revisions[0].Revision)
+	assert.Equal(t, "rrev1", index.Revisions[1].Revision)
+}
+
+func TestPromoteRevisionNotFound(t *testing.T) {
+	servicesManager := newPromoteServicesManager()
+	promotion := newPromotion(false)
+	promotion.Reference.Revision = "missing"
+	promotion.Packages = nil
+	err := Promote(&servicesManager, promotion, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Revision 'missing' not found in 'src/_/b2/4.0.0/_/index.json'", err.Error())
+}
@@ -0,0 +1,112 @@
+package manage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// SetProperties sets the properties `props` (with format 'key1=value1;key2=value2,value3') to the folders in
+// `paths` (they include the repository). If `recursive` is true, they are set to all the files and folders inside.
+// It returns the number of items modified.
+func SetProperties(serviceManager artifactory.ArtifactoryServicesManager, paths []string, props string, recursive bool) (int, error) {
+	reader, err := collectItems(serviceManager, paths, recursive)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	params := services.NewPropsParams()
+	params.Reader = reader
+	params.Props = props
+	return serviceManager.SetProps(params)
+}
+
+// DeleteProperties removes the properties with the given `keys` (with format 'key1,key2') from the folders in `paths`
+// (they include the repository). If `recursive` is true, they are removed from all the files and folders inside.
+// It returns the number of items modified.
+func DeleteProperties(serviceManager artifactory.ArtifactoryServicesManager, paths []string, keys string, recursive bool) (int, error) {
+	reader, err := collectItems(serviceManager, paths, recursive)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	params := services.NewPropsParams()
+	params.Reader = reader
+	params.Props = keys
+	return serviceManager.DeleteProps(params)
+}
+
+// collectItems returns a reader with the folders in `paths` and, if `recursive`, all the items inside them.
+func collectItems(serviceManager artifactory.ArtifactoryServicesManager, paths []string, recursive bool) (*content.ContentReader, error) {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		writer.Write(folderItem(path))
+		if !recursive {
+			continue
+		}
+
+		params := services.NewSearchParams()
+		params.Pattern = path + "/"
+		params.Recursive = true
+		params.IncludeDirs = true
+		log.Debug(fmt.Sprintf("Search items inside '%s'", path))
+		reader, err := serviceManager.SearchFiles(params)
+		if err != nil {
+			writer.Close()
+			writer.RemoveOutputFilePath()
+			return nil, err
+		}
+		for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+			writer.Write(*resultItem)
+		}
+		reader.Close()
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return content.NewContentReader(writer.GetFilePath(), content.DefaultKey), nil
+}
+
+func folderItem(path string) servicesUtils.ResultItem {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	item := servicesUtils.ResultItem{Repo: parts[0], Path: ".", Type: "folder"}
+	if len(parts) > 1 {
+		item.Path = strings.Join(parts[1:len(parts)-1], "/")
+		item.Name = parts[len(parts)-1]
+		if item.Path == "" {
+			item.Path = "."
+		}
+	}
+	return item
+}
+
+// PropertiesString returns the given `properties` with the format used by `SetProperties`. Values of repeated keys
+// are joined (multi-valued properties) and keys are kept in order of appearance. Artifactory doesn't accept empty
+// values nor values containing ';', those are skipped.
+func PropertiesString(properties []servicesUtils.Property) string {
+	keys := []string{}
+	values := make(map[string][]string)
+	for _, prop := range properties {
+		if prop.Value == "" || strings.Contains(prop.Value, ";") {
+			log.Debug(fmt.Sprintf("Skip value '%s' for property '%s'", prop.Value, prop.Key))
+			continue
+		}
+		if _, ok := values[prop.Key]; !ok {
+			keys = append(keys, prop.Key)
+		}
+		values[prop.Key] = append(values[prop.Key], prop.Value)
+	}
+	items := []string{}
+	for _, key := range keys {
+		items = append(items, key+"="+strings.Join(values[key], ","))
+	}
+	return strings.Join(items, ";")
+}
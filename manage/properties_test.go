@@ -0,0 +1,51 @@
+package manage
+
+import (
+	"testing"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetProperties(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	paths := []string{"repo/_/b2/4.0.0/_/rrev", "repo/_/b2/4.0.0/_/rrev/package/pkgID/prev"}
+	n, err := SetProperties(&servicesManager, paths, "key=value;other=v1,v2", false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "key=value;other=v1,v2", servicesManager.props)
+	assert.Equal(t, []string{"repo/_/b2/4.0.0/_/rrev/", "repo/_/b2/4.0.0/_/rrev/package/pkgID/prev/"}, servicesManager.items)
+}
+
+func TestDeletePropertiesRecursive(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	n, err := DeleteProperties(&servicesManager, []string{"repo/_/b2/4.0.0/_/rrev"}, "key,other", true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "key,other", servicesManager.props)
+	assert.Equal(t, []string{
+		"repo/_/b2/4.0.0/_/rrev/",
+		"repo/_/b2/4.0.0/_/rrev/export/",
+		"repo/_/b2/4.0.0/_/rrev/export/conanfile.py",
+	}, servicesManager.items)
+}
+
+func TestFolderItem(t *testing.T) {
+	item := folderItem("repo/_")
+	assert.Equal(t, "repo", item.Repo)
+	assert.Equal(t, ".", item.Path)
+	assert.Equal(t, "_", item.Name)
+	assert.Equal(t, "repo/_/b2/", folderItem("repo/_/b2/").GetItemRelativePath())
+}
+
+func TestPropertiesString(t *testing.T) {
+	properties := []servicesUtils.Property{
+		{Key: "topics", Value: "conan"},
+		{Key: "license", Value: "MIT"},
+		{Key: "topics", Value: "builder"},
+		{Key: "deprecated", Value: ""},
+		{Key: "description", Value: "first; second"},
+	}
+	assert.Equal(t, "topics=conan,builder;license=MIT", PropertiesString(properties))
+	assert.Equal(t, "", PropertiesString(nil))
+}
@@ -0,0 +1,128 @@
+package manage
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/utils/log"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+)
+
+const (
+	contentSearchFiles = `{"results": [
+		{"repo": "repo", "path": "_/b2/4.0.0/_/rrev", "name": "export", "type": "folder"},
+		{"repo": "repo", "path": "_/b2/4.0.0/_/rrev/export", "name": "conanfile.py", "type": "file"}
+	]}`
+	contentRevisions = `{
+		"reference": "b2/4.0.0@_/_",
+		"revisions": [{
+			"revision": "rrev3",
+			"time": "2020-09-16T14:05:05.965+0000"
+		}, {
+			"revision": "rrev2",
+			"time": "2020-08-17T15:20:47.871+0000"
+		}, {
+			"revision": "rrev1",
+			"time": "2020-08-15T15:20:47.871+0000"
+		}]
+	}`
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+// MockArtifactoryServicesManager records the calls made by the manage functions and returns the contents in `files`
+// and the search `results` for each pattern.
+type MockArtifactoryServicesManager struct {
+	artifactory.EmptyArtifactoryServicesManager
+	deleted     []string
+	uploaded    map[string]string
+	failOn      string
+	props       string
+	items       []string
+	files       map[string]string
+	results     map[string]string
+	transferred []string
+}
+
+func (esm *MockArtifactoryServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser, error) {
+	if fileContent, ok := esm.files[readPath]; ok {
+		return ioutil.NopCloser(strings.NewReader(fileContent)), nil
+	}
+	return ioutil.NopCloser(strings.NewReader(contentRevisions)), nil
+}
+
+func (esm *MockArtifactoryServicesManager) GetPathsToDelete(params services.DeleteParams) (*content.ContentReader, error) {
+	if params.Pattern == esm.failOn {
+		return nil, errors.New("cannot delete")
+	}
+	esm.deleted = append(esm.deleted, params.Pattern)
+	tmpFile, _ := ioutil.TempFile(os.TempDir(), "prefix-")
+	tmpFile.Close()
+	return content.NewContentReader(tmpFile.Name(), "results"), nil
+}
+
+func (esm *MockArtifactoryServicesManager) DeleteFiles(reader *content.ContentReader) (int, error) {
+	return 1, nil
+}
+
+func (esm *MockArtifactoryServicesManager) UploadFiles(params ...services.UploadParams) (int, int, error) {
+	if esm.uploaded == nil {
+		esm.uploaded = make(map[string]string)
+	}
+	for _, p := range params {
+		b, _ := ioutil.ReadFile(p.Pattern)
+		esm.uploaded[p.Target] = string(b)
+	}
+	return len(params), 0, nil
+}
+
+func (esm *MockArtifactoryServicesManager) Copy(params services.MoveCopyParams) (int, int, error) {
+	esm.transferred = append(esm.transferred, "copy "+params.Pattern+" "+params.Target)
+	return 1, 0, nil
+}
+
+func (esm *MockArtifactoryServicesManager) Move(params services.MoveCopyParams) (int, int, error) {
+	esm.transferred = append(esm.transferred, "move "+params.Pattern+" "+params.Target)
+	return 1, 0, nil
+}
+
+func (esm *MockArtifactoryServicesManager) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
+	tmpFile, _ := ioutil.TempFile(os.TempDir(), "prefix-")
+	if params.Pattern == "repo/_/b2/4.0.0/_/rrev/" {
+		tmpFile.WriteString(contentSearchFiles)
+	} else if results, ok := esm.results[params.Pattern]; ok {
+		tmpFile.WriteString(results)
+	} else if _, ok := esm.files[params.Pattern]; ok {
+		tmpFile.WriteString(`{"results": [{"repo": "repo", "path": ".", "name": "index.json", "type": "file"}]}`)
+	} else {
+		tmpFile.WriteString(`{"results": []}`)
+	}
+	tmpFile.Close()
+	return content.NewContentReader(tmpFile.Name(), "results"), nil
+}
+
+func (esm *MockArtifactoryServicesManager) SetProps(params services.PropsParams) (int, error) {
+	esm.props = params.Props
+	return esm.readItems(params.Reader), nil
+}
+
+func (esm *MockArtifactoryServicesManager) DeleteProps(params services.PropsParams) (int, error) {
+	esm.props = params.Props
+	return esm.readItems(params.Reader), nil
+}
+
+func (esm *MockArtifactoryServicesManager) readItems(reader *content.ContentReader) int {
+	esm.items = nil
+	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+		esm.items = append(esm.items, resultItem.GetItemRelativePath())
+	}
+	return len(esm.items)
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Reference is the structured representation of a `types.Reference` stored in an Artifactory repository.
+type Reference struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	User     string `json:"user"`
+	Channel  string `json:"channel"`
+	Revision string `json:"revision"`
+	Path     string `json:"path"`
+}
+
+// NewReference creates a `Reference` for the given `ref` stored in the `repository`.
+func NewReference(repository string, ref types.Reference) Reference {
+	item := Reference{
+		Name:     ref.Name,
+		Version:  ref.Version,
+		Revision: ref.Revision,
+		Path:     repository + "/" + ref.RtPath(true),
+	}
+	if ref.User != nil {
+		item.User = *ref.User
+	}
+	if ref.Channel != nil {
+		item.Channel = *ref.Channel
+	}
+	return item
+}
+
+// Package is the structured representation of a `types.Package` stored in an Artifactory repository.
+type Package struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	User            string `json:"user"`
+	Channel         string `json:"channel"`
+	Revision        string `json:"revision"`
+	PackageID       string `json:"package_id"`
+	PackageRevision string `json:"package_revision"`
+	Path            string `json:"path"`
+}
+
+// NewPackage creates a `Package` for the given `pkg` stored in the `repository`.
+func NewPackage(repository string, pkg types.Package) Package {
+	ref := NewReference(repository, pkg.Ref)
+	return Package{
+		Name:            ref.Name,
+		Version:         ref.Version,
+		User:            ref.User,
+		Channel:         ref.Channel,
+		Revision:        ref.Revision,
+		PackageID:       pkg.PackageId,
+		PackageRevision: pkg.Revision,
+		Path:            repository + "/" + pkg.RtPath(true),
+	}
+}
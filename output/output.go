@@ -0,0 +1,79 @@
+// Package output contains the types and functions used to render the results of the commands
+// in a machine-readable way.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Format identifies the representation used to print the results of a command.
+type Format string
+
+// Formats supported by the commands. Not every command supports all of them.
+const (
+	Text      Format = "text"  // Human readable output (default)
+	JSON      Format = "json"  // A single JSON document
+	JSONLines Format = "jsonl" // One JSON document per line
+	YAML      Format = "yaml"  // A single YAML document
+	DOT       Format = "dot"   // Graphviz DOT language (only for graphs)
+)
+
+// ParseFormat validates the `value` given by the user against the list of `allowed` formats. An empty
+// value defaults to `Text`.
+func ParseFormat(value string, allowed ...Format) (Format, error) {
+	if value == "" {
+		return Text, nil
+	}
+	names := []string{}
+	for _, format := range allowed {
+		if string(format) == value {
+			return format, nil
+		}
+		names = append(names, string(format))
+	}
+	return "", fmt.Errorf("Invalid format '%s'. Expected one of: %s", value, strings.Join(names, ", "))
+}
+
+// Marshal returns the representation of `items` using the given `format`: a single JSON or YAML
+// document for `JSON` and `YAML`, and one compact JSON object per line for `JSONLines` (then
+// `items` must be a slice).
+func Marshal(format Format, items interface{}) (string, error) {
+	switch format {
+	case JSON:
+		value := reflect.ValueOf(items)
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return "[]", nil
+		}
+		b, err := json.MarshalIndent(items, "", "\t")
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case JSONLines:
+		value := reflect.ValueOf(items)
+		if value.Kind() != reflect.Slice {
+			return "", fmt.Errorf("Format '%s' requires a list of items", format)
+		}
+		lines := []string{}
+		for i := 0; i < value.Len(); i++ {
+			b, err := json.Marshal(value.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, string(b))
+		}
+		return strings.Join(lines, "\n"), nil
+	case YAML:
+		b, err := yaml.Marshal(items)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\n"), nil
+	}
+	return "", fmt.Errorf("Format '%s' cannot be marshalled", format)
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("", Text, JSON)
+	assert.Nil(t, err)
+	assert.Equal(t, Text, format)
+
+	format, err = ParseFormat("json", Text, JSON)
+	assert.Nil(t, err)
+	assert.Equal(t, JSON, format)
+
+	_, err = ParseFormat("jsonl", Text, JSON)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid format 'jsonl'. Expected one of: text, json", err.Error())
+}
+
+func TestMarshal(t *testing.T) {
+	user := "user"
+	channel := "channel"
+	items := []Reference{
+		NewReference("repo", types.Reference{Name: "name", Version: "version", Revision: "rrev"}),
+		NewReference("repo", types.Reference{Name: "name", Version: "version", User: &user, Channel: &channel, Revision: "rrev"}),
+	}
+
+	str, err := Marshal(JSONLines, items)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"name","version":"version","user":"","channel":"","revision":"rrev","path":"repo/_/name/version/_/rrev"}
+{"name":"name","version":"version","user":"user","channel":"channel","revision":"rrev","path":"repo/user/name/version/channel/rrev"}`, str)
+
+	str, err = Marshal(JSON, items[:1])
+	assert.Nil(t, err)
+	assert.Equal(t, "[\n\t{\n\t\t\"name\": \"name\",\n\t\t\"version\": \"version\",\n\t\t\"user\": \"\",\n\t\t\"channel\": \"\",\n\t\t\"revision\": \"rrev\",\n\t\t\"path\": \"repo/_/name/version/_/rrev\"\n\t}\n]", str)
+
+	var empty []Reference
+	str, err = Marshal(JSON, empty)
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", str)
+
+	str, err = Marshal(JSONLines, empty)
+	assert.Nil(t, err)
+	assert.Equal(t, "", str)
+
+	_, err = Marshal(Text, items)
+	assert.NotNil(t, err)
+}
+
+func TestNewPackage(t *testing.T) {
+	ref := types.Reference{Name: "name", Version: "version", Revision: "rrev"}
+	item := NewPackage("repo", types.Package{Ref: ref, PackageId: "pkgID", Revision: "prev"})
+	assert.Equal(t, Package{Name: "name", Version: "version", Revision: "rrev", PackageID: "pkgID", PackageRevision: "prev", Path: "repo/_/name/version/_/rrev/package/pkgID/prev"}, item)
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"sort"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+)
+
+// MultiValuedProperties lists the property keys that are always rendered as arrays, even if
+// they contain a single value (or none).
+var MultiValuedProperties = []string{"topics", "settings", "options", "requires"}
+
+// Properties is the structured representation of the Artifactory properties of an item. Values are
+// either a string or a sorted list of strings (for multi-valued keys).
+type Properties map[string]interface{}
+
+// NewProperties groups the given `props` by key. Keys listed in `MultiValuedProperties` or found
+// more than once become sorted arrays; empty values are dropped from these arrays. Any other
+// key keeps its (possibly empty) string value.
+func NewProperties(props []servicesUtils.Property) Properties {
+	values := make(map[string][]string)
+	for _, prop := range props {
+		values[prop.Key] = append(values[prop.Key], prop.Value)
+	}
+
+	ret := make(Properties)
+	for key, value := range values {
+		if len(value) == 1 && !isMultiValued(key) {
+			ret[key] = value[0]
+			continue
+		}
+		list := []string{}
+		for _, v := range value {
+			if len(v) > 0 {
+				list = append(list, v)
+			}
+		}
+		sort.Strings(list)
+		ret[key] = list
+	}
+	return ret
+}
+
+func isMultiValued(key string) bool {
+	for _, k := range MultiValuedProperties {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ReferenceProperties contains the properties of a reference and, optionally, the ones of its packages
+// keyed by package ID and package revision.
+type ReferenceProperties struct {
+	Properties Properties            `json:"properties" yaml:"properties"`
+	Packages   map[string]Properties `json:"packages,omitempty" yaml:"packages,omitempty"`
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"testing"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProperties(t *testing.T) {
+	props := []servicesUtils.Property{}
+	props = append(props, servicesUtils.Property{Key: "topics", Value: "conan"})
+	props = append(props, servicesUtils.Property{Key: "topics", Value: "builder"})
+	props = append(props, servicesUtils.Property{Key: "settings", Value: "os"})
+	props = append(props, servicesUtils.Property{Key: "options"})
+	props = append(props, servicesUtils.Property{Key: "deprecated"})
+	props = append(props, servicesUtils.Property{Key: "license", Value: "MIT"})
+	props = append(props, servicesUtils.Property{Key: "provides", Value: "b"})
+	props = append(props, servicesUtils.Property{Key: "provides", Value: "a"})
+
+	properties := NewProperties(props)
+	assert.Equal(t, Properties{
+		"topics":     []string{"builder", "conan"},
+		"settings":   []string{"os"},
+		"options":    []string{},
+		"deprecated": "",
+		"license":    "MIT",
+		"provides":   []string{"a", "b"},
+	}, properties)
+}
+
+func TestMarshalReferenceProperties(t *testing.T) {
+	props := []servicesUtils.Property{}
+	props = append(props, servicesUtils.Property{Key: "topics", Value: "conan"})
+	props = append(props, servicesUtils.Property{Key: "deprecated"})
+	pkgProps := []servicesUtils.Property{}
+	pkgProps = append(pkgProps, servicesUtils.Property{Key: "settings", Value: "os=Linux"})
+
+	document := map[string]ReferenceProperties{
+		"name/version#rrev": {
+			Properties: NewProperties(props),
+			Packages:   map[string]Properties{"pkgID#prev": NewProperties(pkgProps)},
+		},
+	}
+
+	str, err := Marshal(YAML, document)
+	assert.Nil(t, err)
+	assert.Equal(t, `name/version#rrev:
+  properties:
+    deprecated: ""
+    topics:
+    - conan
+  packages:
+    pkgID#prev:
+      settings:
+      - os=Linux`, str)
+
+	str, err = Marshal(JSON, document)
+	assert.Nil(t, err)
+	assert.Equal(t, `{
+	"name/version#rrev": {
+		"properties": {
+			"deprecated": "",
+			"topics": [
+				"conan"
+			]
+		},
+		"packages": {
+			"pkgID#prev": {
+				"settings": [
+					"os=Linux"
+				]
+			}
+		}
+	}
+}`, str)
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"fmt"
+)
+
+// UnexpectedPathError is returned for paths found in the repository that don't match the layout or don't belong to
+// the reference searched.
+type UnexpectedPathError struct {
+	Path   string
+	Reason string
+}
+
+func (e *UnexpectedPathError) Error() string {
+	return fmt.Sprintf("Unexpected path '%s': %s", e.Path, e.Reason)
+}
+
+// MissingIndexError is returned when an 'index.json' file cannot be read (`Err` is the cause) or it lists no
+// revisions (`Err` is nil).
+type MissingIndexError struct {
+	Path string
+	Err  error
+}
+
+func (e *MissingIndexError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("No revisions listed in '%s'", e.Path)
+	}
+	return fmt.Sprintf("Cannot read revisions from '%s': %s", e.Path, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *MissingIndexError) Unwrap() error {
+	return e.Err
+}
+
+// RevisionNotFoundError is returned when a revision listed in an 'index.json' file is not found in the repository.
+type RevisionNotFoundError struct {
+	Reference string
+	Revision  string
+}
+
+func (e *RevisionNotFoundError) Error() string {
+	return fmt.Sprintf("Revision '%s' of '%s' listed in 'index.json' not found in the repository", e.Revision, e.Reference)
+}
+
+// Mode defines how the search functions handle malformed entries (any of the errors above).
+type Mode int
+
+const (
+	// Strict mode fails on the first malformed entry.
+	Strict Mode = iota
+	// Lenient mode skips malformed entries, they can be retrieved using `Options.Skipped`.
+	Lenient
+)
+
+// Options configure the search functions and the revision resolvers: the `Mode`, the `Fallback` policy and the
+// revision `Source`. A nil `*Options` (or its zero value) uses `Strict` mode, `FallbackNewest` and `IndexSource`.
+type Options struct {
+	Mode     Mode
+	Fallback LatestFallback
+	Source   RevisionSource
+	skipped  []error
+}
+
+// Skipped returns the errors of the entries skipped in `Lenient` mode.
+func (options *Options) Skipped() []error {
+	if options == nil {
+		return nil
+	}
+	return append([]error{}, options.skipped...)
+}
+
+// handleMalformed returns `err` in `Strict` mode. In `Lenient` mode it records the error and returns nil, so the
+// caller skips the entry.
+func (options *Options) handleMalformed(err error) error {
+	if options == nil || options.Mode == Strict {
+		return err
+	}
+	options.skipped = append(options.skipped, err)
+	return nil
+}
+
+func (options *Options) fallback() LatestFallback {
+	if options == nil || options.Fallback == "" {
+		return FallbackNewest
+	}
+	return options.Fallback
+}
+
+func (options *Options) source() RevisionSource {
+	if options == nil || options.Source == "" {
+		return IndexSource
+	}
+	return options.Source
+}
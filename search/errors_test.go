@@ -0,0 +1,147 @@
+package search
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockMalformedServicesManager returns the given files and the contents of the 'index.json' files in `indexes`.
+type MockMalformedServicesManager struct {
+	MockLayoutServicesManager
+	indexes map[string]string
+}
+
+func (esm *MockMalformedServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser, error) {
+	if content, ok := esm.indexes[readPath]; ok {
+		return ioutil.NopCloser(strings.NewReader(content)), nil
+	}
+	return nil, errors.New("404 Not Found")
+}
+
+func newMalformedServicesManager() *MockMalformedServicesManager {
+	serviceManager := &MockMalformedServicesManager{indexes: map[string]string{
+		"repository/_/b2/4.0.0/_/index.json":                     `{"reference": "b2/4.0.0@_/_", "revisions": [{"revision": "rrev1", "time": "2020-08-17T15:20:47.871+0000"}]}`,
+		"repository/_/b2/4.0.1/_/index.json":                     `{"reference": "b2/4.0.1@_/_", "revisions": [{"revision": "rrev3", "time": "2020-08-17T15:20:47.871+0000"}]}`,
+		"repository/_/b2/4.0.0/_/rrev1/package/pkgid/index.json": `{"packageReference": "b2/4.0.0@_/_#rrev1:pkgid", "revisions": []}`,
+	}}
+	serviceManager.files = []servicesUtils.ResultItem{
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev2/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.1/_/rrev1/export", Name: "conanfile.py", Created: "2020-09-16T14:05:05.965Z"},
+		{Repo: "repository", Path: "_/b2/4.0.1/_/rrev2/export", Name: "conanfile.py", Created: "2020-08-17T15:20:47.871Z"},
+		{Repo: "repository", Path: "_/b2/4.0.2/_/rrev1/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.2/_/rrev2/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev1", Name: "conaninfo.txt"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev2", Name: "conaninfo.txt"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid2/prev1", Name: "conaninfo.txt"},
+		{Repo: "repository", Path: "_/zlib/1.2.11/_/rrev1/package/pkgid/prev1", Name: "conaninfo.txt"},
+	}
+	return serviceManager
+}
+
+func TestErrors(t *testing.T) {
+	err := error(&UnexpectedPathError{Path: "_/b2/4.0.0/export", Reason: "not a recipe revision"})
+	assert.Equal(t, "Unexpected path '_/b2/4.0.0/export': not a recipe revision", err.Error())
+
+	cause := errors.New("404 Not Found")
+	err = &MissingIndexError{Path: "repository/_/b2/4.0.0/_/index.json", Err: cause}
+	assert.Equal(t, "Cannot read revisions from 'repository/_/b2/4.0.0/_/index.json': 404 Not Found", err.Error())
+	assert.True(t, errors.Is(err, cause))
+	err = &MissingIndexError{Path: "repository/_/b2/4.0.0/_/index.json"}
+	assert.Equal(t, "No revisions listed in 'repository/_/b2/4.0.0/_/index.json'", err.Error())
+
+	err = &RevisionNotFoundError{Reference: "b2/4.0.0", Revision: "rrev"}
+	assert.Equal(t, "Revision 'rrev' of 'b2/4.0.0' listed in 'index.json' not found in the repository", err.Error())
+}
+
+func TestSearchReferencesStrict(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	options := &Options{Mode: Strict}
+
+	_, err := SearchReferences(serviceManager, "repository", "b2", false, nil, options)
+	var unexpectedPath *UnexpectedPathError
+	assert.True(t, errors.As(err, &unexpectedPath))
+	assert.Equal(t, "_/b2/4.0.0/export", unexpectedPath.Path)
+
+	serviceManager.files = serviceManager.files[:6]
+	_, err = SearchReferences(serviceManager, "repository", "b2", true, nil, options)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(options.Skipped()))
+}
+
+func TestSearchReferencesLenient(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	options := &Options{Mode: Lenient, Fallback: FallbackNone}
+
+	references, err := SearchReferences(serviceManager, "repository", "b2", true, nil, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(references))
+	assert.Equal(t, "b2/4.0.0#rrev1", references[0].String())
+
+	skipped := options.Skipped()
+	assert.Equal(t, 3, len(skipped))
+	var unexpectedPath *UnexpectedPathError
+	var missingIndex *MissingIndexError
+	var revisionNotFound *RevisionNotFoundError
+	for _, err := range skipped {
+		switch {
+		case errors.As(err, &unexpectedPath):
+			assert.Equal(t, "_/b2/4.0.0/export", unexpectedPath.Path)
+		case errors.As(err, &missingIndex):
+			assert.Equal(t, "repository/_/b2/4.0.2/_/index.json", missingIndex.Path)
+		case errors.As(err, &revisionNotFound):
+			assert.Equal(t, "b2/4.0.1", revisionNotFound.Reference)
+			assert.Equal(t, "rrev3", revisionNotFound.Revision)
+		}
+	}
+	assert.NotNil(t, unexpectedPath)
+	assert.NotNil(t, missingIndex)
+	assert.NotNil(t, revisionNotFound)
+}
+
+func TestSearchPackagesLenient(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+
+	// The mock returns packages of other references, they used to panic
+	_, err := SearchPackages(serviceManager, "repository", "b2", false, false, nil, nil)
+	var unexpectedPath *UnexpectedPathError
+	assert.True(t, errors.As(err, &unexpectedPath))
+	assert.Equal(t, "_/zlib/1.2.11/_/rrev1/package/pkgid/prev1", unexpectedPath.Path)
+
+	options := &Options{Mode: Lenient}
+	packages, err := SearchPackages(serviceManager, "repository", "b2", false, true, nil, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(packages))
+	messages := []string{}
+	for _, err := range options.Skipped() {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, 3, len(messages))
+	assert.Contains(t, messages, "No revisions listed in 'repository/_/b2/4.0.0/_/rrev1/package/pkgid/index.json'")
+	assert.Contains(t, messages, "Cannot read revisions from 'repository/_/b2/4.0.0/_/rrev1/package/pkgid2/index.json': 404 Not Found")
+}
+
+func TestSearchPackagesLatestWithoutPackages(t *testing.T) {
+	// The latest recipe revision (rrev3) has no packages, it is not a malformed entry
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.0/_/index.json"] = `{"reference": "b2/4.0.0@_/_", "revisions": [
+		{"revision": "rrev1", "time": "2020-08-17T15:20:47.871+0000"},
+		{"revision": "rrev2", "time": "2020-08-18T15:20:47.871+0000"},
+		{"revision": "rrev3", "time": "2020-08-19T15:20:47.871+0000"}]}`
+	serviceManager.files = append(serviceManager.files[7:9],
+		servicesUtils.ResultItem{Repo: "repository", Path: "_/b2/4.0.0/_/rrev2/package/pkgid/prev1", Name: "conaninfo.txt"},
+		servicesUtils.ResultItem{Repo: "repository", Path: "_/b2/4.0.0/_/rrev3/export", Name: "conanfile.py"})
+
+	options := &Options{Mode: Strict}
+	packages, err := SearchPackages(serviceManager, "repository", "b2", true, false, nil, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(packages))
+	assert.Equal(t, 0, len(options.Skipped()))
+}
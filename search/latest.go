@@ -0,0 +1,91 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// LatestFallback defines what to do when the latest revision listed in an 'index.json' file is not found in the
+// repository.
+type LatestFallback string
+
+const (
+	// FallbackNewest uses the newest revision found in the repository instead and warns about the mismatch.
+	FallbackNewest LatestFallback = "newest"
+	// FallbackNone fails with a `RevisionNotFoundError` (or skips the entry in `Lenient` mode).
+	FallbackNone LatestFallback = "none"
+)
+
+// ParseLatestFallback returns the fallback policy with the given name ('newest' or 'none').
+func ParseLatestFallback(name string) (LatestFallback, error) {
+	for _, fallback := range []LatestFallback{FallbackNewest, FallbackNone} {
+		if string(fallback) == strings.ToLower(name) {
+			return fallback, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid fallback '%s'. Expected one of: newest, none", name)
+}
+
+// Candidate is a revision found in the repository together with the time it was created in Artifactory.
+type Candidate struct {
+	Revision string
+	Created  time.Time
+}
+
+// resolveLatest returns the position in `candidates` of the latest revision listed in `rtRevisions` (sorted by time)
+// for the reference or package `name`. If it is not found, using `FallbackNewest` it returns the newest candidate,
+// with the time listed in the 'index.json' or, for revisions not listed, the time they were created in Artifactory;
+// otherwise it fails with a `RevisionNotFoundError`.
+func resolveLatest(name string, rtRevisions []types.RtRevisionsData, candidates []Candidate, fallback LatestFallback) (int, error) {
+	latestRevision := rtRevisions[len(rtRevisions)-1].Revision
+	if i := Search(len(candidates), func(i int) bool { return candidates[i].Revision == latestRevision }); i != -1 {
+		return i, nil
+	}
+	err := &RevisionNotFoundError{Reference: name, Revision: latestRevision}
+	if fallback != FallbackNewest || len(candidates) == 0 {
+		return -1, err
+	}
+
+	newest := newestTime(revisionTimes(rtRevisions, candidates))
+	log.Warn(fmt.Sprintf("%s, using the newest revision found '%s'", err, candidates[newest].Revision))
+	return newest, nil
+}
+
+// revisionTimes returns the time of each of the `candidates`: the one listed in `rtRevisions` or, for revisions not
+// listed, the time they were created in Artifactory.
+func revisionTimes(rtRevisions []types.RtRevisionsData, candidates []Candidate) []time.Time {
+	listed := make(map[string]time.Time)
+	for _, rtRevision := range rtRevisions {
+		listed[rtRevision.Revision] = rtRevision.Time.Time
+	}
+	times := []time.Time{}
+	for _, candidate := range candidates {
+		revisionTime, ok := listed[candidate.Revision]
+		if !ok {
+			revisionTime = candidate.Created
+		}
+		times = append(times, revisionTime)
+	}
+	return times
+}
+
+// newestTime returns the position of the newest of the `times` (the first one on ties, -1 if empty).
+func newestTime(times []time.Time) int {
+	newest := -1
+	for i, t := range times {
+		if newest == -1 || t.After(times[newest]) {
+			newest = i
+		}
+	}
+	return newest
+}
+
+// parseCreated returns the creation time of an item found in Artifactory (zero if it cannot be parsed).
+func parseCreated(value string) time.Time {
+	created, _ := time.Parse(time.RFC3339, value)
+	return created
+}
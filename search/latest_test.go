@@ -0,0 +1,108 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLatestFallback(t *testing.T) {
+	fallback, err := ParseLatestFallback("None")
+	assert.Nil(t, err)
+	assert.Equal(t, FallbackNone, fallback)
+
+	_, err = ParseLatestFallback("oldest")
+	assert.Equal(t, "Invalid fallback 'oldest'. Expected one of: newest, none", err.Error())
+}
+
+func TestResolveLatest(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	rtRevisions := []types.RtRevisionsData{
+		{Revision: "rrev1", Time: types.RtTimestamp{Time: day(1)}},
+		{Revision: "rrev2", Time: types.RtTimestamp{Time: day(2)}},
+		{Revision: "rrev3", Time: types.RtTimestamp{Time: day(3)}},
+	}
+
+	// Latest listed revision is present
+	i, err := resolveLatest("b2/4.0.0", rtRevisions, []Candidate{{"rrev1", day(1)}, {"rrev3", day(1)}, {"rrev2", day(1)}}, FallbackNewest)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, i)
+
+	// Newest present revision using the time in 'index.json'
+	i, err = resolveLatest("b2/4.0.0", rtRevisions, []Candidate{{"rrev2", day(1)}, {"rrev1", day(5)}}, FallbackNewest)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+
+	// Revisions not listed use the creation time in Artifactory
+	i, err = resolveLatest("b2/4.0.0", rtRevisions, []Candidate{{"rrev2", day(1)}, {"rrev4", day(5)}}, FallbackNewest)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, i)
+
+	// No fallback
+	i, err = resolveLatest("b2/4.0.0", rtRevisions, []Candidate{{"rrev2", day(1)}, {"rrev1", day(5)}}, FallbackNone)
+	assert.Equal(t, -1, i)
+	assert.Equal(t, &RevisionNotFoundError{Reference: "b2/4.0.0", Revision: "rrev3"}, err)
+}
+
+func TestSearchReferencesLatestFallback(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.files = serviceManager.files[:4]
+
+	// 'rrev3' is listed in the 'index.json' of 'b2/4.0.1' but it is not found, the newest created one is used
+	references, err := SearchReferences(serviceManager, "repository", "b2", true, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(references))
+	assert.Equal(t, "b2/4.0.0#rrev1", references[0].String())
+	assert.Equal(t, "b2/4.0.1#rrev1", references[1].String())
+
+	_, err = SearchReferences(serviceManager, "repository", "b2", true, nil, &Options{Fallback: FallbackNone})
+	assert.Equal(t, &RevisionNotFoundError{Reference: "b2/4.0.1", Revision: "rrev3"}, err)
+}
+
+func TestSearchPackagesLatestFallback(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.0/_/rrev1/package/pkgid/index.json"] = `{"revisions": [
+		{"revision": "prev1", "time": "2021-03-01T00:00:00.000+0000"},
+		{"revision": "prev3", "time": "2021-03-03T00:00:00.000+0000"}]}`
+	serviceManager.indexes["repository/_/b2/4.0.0/_/rrev1/package/pkgid2/index.json"] = `{"revisions": [
+		{"revision": "prev1", "time": "2021-03-01T00:00:00.000+0000"}]}`
+	serviceManager.files = []servicesUtils.ResultItem{
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev1", Name: "conaninfo.txt", Created: "2021-03-01T00:00:00.000Z"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev2", Name: "conaninfo.txt", Created: "2021-03-02T00:00:00.000Z"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid2/prev1", Name: "conaninfo.txt", Created: "2021-03-01T00:00:00.000Z"},
+	}
+
+	// 'prev3' is not found, 'prev2' is not listed but it was created after 'prev1'
+	packages, err := SearchPackages(serviceManager, "repository", "b2", false, true, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(packages))
+	assert.Equal(t, "b2/4.0.0#rrev1:pkgid#prev2", packages[0].String())
+	assert.Equal(t, "b2/4.0.0#rrev1:pkgid2#prev1", packages[1].String())
+
+	_, err = SearchPackages(serviceManager, "repository", "b2", false, true, nil, &Options{Fallback: FallbackNone})
+	assert.Equal(t, &RevisionNotFoundError{Reference: "b2/4.0.0#rrev1:pkgid", Revision: "prev3"}, err)
+}
+
+func TestSearchPackagesLatestRecipeWithoutPackages(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.0/_/index.json"] = `{"revisions": [
+		{"revision": "rrev1", "time": "2021-03-01T00:00:00.000+0000"},
+		{"revision": "rrev2", "time": "2021-03-02T00:00:00.000+0000"}]}`
+	serviceManager.files = []servicesUtils.ResultItem{
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev2/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev1", Name: "conaninfo.txt"},
+	}
+
+	// Only 'rrev1' has packages, but 'rrev2' is the latest recipe revision
+	packages, err := SearchPackages(serviceManager, "repository", "b2", true, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(packages))
+
+	packages, err = SearchPackages(serviceManager, "repository", "b2", false, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(packages))
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"regexp"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Layout of the Conan repositories in Artifactory: recipe revisions are stored in the folders
+// 'user/name/version/channel/rrev/export' and package revisions in 'user/name/version/channel/rrev/package/pkgId/prev',
+// using '_' for a null user or channel. Conan 1 and Conan 2 clients upload to this same folder scheme (Conan 2 adds
+// references with user and no channel, stored as 'user/name/version/_') and both upload the 'conanfile.py' to the
+// export folder and the 'conaninfo.txt' to the package folders, so these files are used to find the revisions in any
+// repository, also in the ones that contain uploads from both clients.
+const (
+	recipeAnchor  = "conanfile.py"
+	packageAnchor = "conaninfo.txt"
+)
+
+var (
+	referencePathPattern = regexp.MustCompile(`^(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)\/(?P<revision>[a-z0-9]+)\/export$`)
+	packagePathPattern   = regexp.MustCompile(`^(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)\/(?P<revision>[a-z0-9]+)\/package\/(?P<pkgId>[a-z0-9]*)\/(?P<pkgRev>[a-z0-9]+)$`)
+)
+
+// referencesPattern returns the search pattern for the anchor files of the recipe revisions with the given
+// `referenceName` (all of them if empty).
+func referencesPattern(repository string, referenceName string) string {
+	if len(referenceName) == 0 {
+		referenceName = "*"
+	}
+	return repository + "/*/" + referenceName + "/*/*/export/" + recipeAnchor
+}
+
+// packagesPattern returns the search pattern for the anchor files of the package revisions of the references with the
+// given `referenceName` (all of them if empty).
+func packagesPattern(repository string, referenceName string) string {
+	if len(referenceName) == 0 {
+		referenceName = "*"
+	}
+	return repository + "/*/" + referenceName + "/*/*/package/*/*/" + packageAnchor
+}
+
+// referencePackagesPattern returns the search pattern for the anchor files of the package revisions of the recipe
+// revision `ref`.
+func referencePackagesPattern(repository string, ref types.Reference) string {
+	return repository + "/" + ref.RtPath(true) + "/package/*/*/" + packageAnchor
+}
+
+// referenceRevisionsPattern returns the search pattern for the anchor files of the recipe revisions of `ref` (its
+// revision is ignored).
+func referenceRevisionsPattern(repository string, ref types.Reference) string {
+	return repository + "/" + ref.RtPath(false) + "/*/export/" + recipeAnchor
+}
+
+// packageRevisionsPattern returns the search pattern for the anchor files of the package revisions of `pkg` (its
+// package revision is ignored).
+func packageRevisionsPattern(repository string, pkg types.Package) string {
+	return repository + "/" + pkg.RtPath(false) + "/*/" + packageAnchor
+}
+
+// parseReferencePath returns the recipe revision for the path (relative to the repository) of its export folder.
+func parseReferencePath(path string) (*types.Reference, error) {
+	m := referencePathPattern.FindStringSubmatch(path)
+	if m == nil {
+		return nil, &UnexpectedPathError{Path: path, Reason: "not a recipe revision"}
+	}
+	reference := types.NewReference(m[2], m[3], m[1], m[4], m[5])
+	return &reference, nil
+}
+
+// parsePackagePath returns the package revision for the path (relative to the repository) of its folder.
+func parsePackagePath(path string) (*types.Package, error) {
+	m := packagePathPattern.FindStringSubmatch(path)
+	if m == nil {
+		return nil, &UnexpectedPathError{Path: path, Reason: "not a package revision"}
+	}
+	return &types.Package{Ref: types.NewReference(m[2], m[3], m[1], m[4], m[5]), PackageId: m[6], Revision: m[7]}, nil
+}
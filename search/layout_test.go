@@ -0,0 +1,96 @@
+package search
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockLayoutServicesManager returns the files whose name matches the anchor at the end of the search pattern and whose
+// path starts with the fixed part of the pattern (up to the first wildcard).
+type MockLayoutServicesManager struct {
+	artifactory.EmptyArtifactoryServicesManager
+	files    []servicesUtils.ResultItem
+	searches []string
+}
+
+func (esm *MockLayoutServicesManager) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
+	esm.searches = append(esm.searches, params.Pattern)
+	items := []servicesUtils.ResultItem{}
+	prefix := strings.SplitN(params.Pattern, "*", 2)[0]
+	for _, item := range esm.files {
+		if strings.HasSuffix(params.Pattern, "/"+item.Name) && strings.HasPrefix(item.Repo+"/"+item.Path+"/", prefix) && strings.Contains(params.Pattern, "/package/") == strings.Contains(item.Path, "/package/") {
+			items = append(items, item)
+		}
+	}
+	b, _ := json.Marshal(map[string][]servicesUtils.ResultItem{"results": items})
+	tmpFile, _ := ioutil.TempFile(os.TempDir(), "prefix-")
+	_, _ = tmpFile.Write(b)
+	tmpFile.Close()
+	return content.NewContentReader(tmpFile.Name(), "results"), nil
+}
+
+func TestLayoutPatterns(t *testing.T) {
+	ref := types.NewReference("b2", "4.0.0", "user", "_", "rrev")
+	assert.Equal(t, "repository/*/b2/*/*/export/conanfile.py", referencesPattern("repository", "b2"))
+	assert.Equal(t, "repository/*/*/*/*/package/*/*/conaninfo.txt", packagesPattern("repository", ""))
+	assert.Equal(t, "repository/user/b2/4.0.0/_/rrev/package/*/*/conaninfo.txt", referencePackagesPattern("repository", ref))
+}
+
+func TestLayoutParse(t *testing.T) {
+	ref, err := parseReferencePath("_/b2/4.0.0/_/rrev/export")
+	assert.Nil(t, err)
+	assert.Equal(t, "b2/4.0.0#rrev", ref.String())
+
+	ref, err = parseReferencePath("user/b2/4.0.0/_/rrev/export")
+	assert.Nil(t, err)
+	assert.Equal(t, "b2/4.0.0@user#rrev", ref.String())
+
+	pkg, err := parsePackagePath("user/b2/4.0.0/channel/rrev/package/pkgid/prev")
+	assert.Nil(t, err)
+	assert.Equal(t, "b2/4.0.0@user/channel#rrev:pkgid#prev", pkg.String())
+
+	_, err = parseReferencePath("_/b2/4.0.0/_/rrev/export_source")
+	assert.Equal(t, "Unexpected path '_/b2/4.0.0/_/rrev/export_source': not a recipe revision", err.Error())
+	_, err = parsePackagePath("_/b2/4.0.0/_/rrev/export")
+	assert.Equal(t, "Unexpected path '_/b2/4.0.0/_/rrev/export': not a package revision", err.Error())
+}
+
+func TestSearchMixedRepository(t *testing.T) {
+	// Conan 1 upload of 'b2/4.0.0' and Conan 2 upload (user without channel) of 'b2/4.0.0@user'
+	serviceManager := &MockLayoutServicesManager{files: []servicesUtils.ResultItem{
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/export", Name: "conanmanifest.txt"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev", Name: "conaninfo.txt"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/export", Name: "conanfile.py"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/export", Name: "conanmanifest.txt"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/export", Name: "conan_export.tgz"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/package/pkgid/prev", Name: "conaninfo.txt"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/package/pkgid/prev", Name: "conanmanifest.txt"},
+		{Repo: "repository", Path: "user/b2/4.0.0/_/rrev2/package/pkgid/prev", Name: "conan_package.tgz"},
+	}}
+	references, err := SearchReferences(serviceManager, "repository", "b2", false, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(references))
+	assert.ElementsMatch(t, []string{"b2/4.0.0#rrev1", "b2/4.0.0@user#rrev2"}, []string{references[0].String(), references[1].String()})
+
+	packages, err := SearchPackages(serviceManager, "repository", "b2", false, false, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(packages))
+
+	ref := types.NewReference("b2", "4.0.0", "user", "_", "rrev2")
+	packages, err = SearchReferencePackages(serviceManager, "repository", ref)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(packages))
+	assert.Equal(t, "b2/4.0.0@user#rrev2:pkgid#prev", packages[0].String())
+	assert.Contains(t, serviceManager.searches, "repository/user/b2/4.0.0/_/rrev2/package/*/*/conaninfo.txt")
+}
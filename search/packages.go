@@ -2,7 +2,9 @@
 package search
 
 import (
-	"regexp"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/jgsogo/jcli-conan-center/types"
 
@@ -12,21 +14,16 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-// SearchPackages returns a list of packages matching the `referenceName` in the given `repository`. Use the argument
-// `onlyLatestRecipe` to retrieve only packages that belong to the latest revision for each reference, and argument
-// `onlyLatestPackage` to retrieve only the latest revision for each package.
-func SearchPackages(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, onlyLatestRecipe bool, onlyLatestPackage bool) ([]types.Package, error) {
+// SearchPackages returns a list of packages matching the `referenceName` in the given `repository`, sorted by reference
+// (name and version), package ID and package revision. Use the argument `onlyLatestRecipe` to retrieve only packages
+// that belong to the latest revision for each reference, argument `onlyLatestPackage` to retrieve only the latest
+// revision for each package and `versionRange` (if not nil) to retrieve only packages whose version satisfies it.
+// Malformed entries and latest revisions are handled according to the `options` (nil for the defaults).
+func SearchPackages(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, onlyLatestRecipe bool, onlyLatestPackage bool, versionRange *types.VersionRange, options *Options) ([]types.Package, error) {
 	log.Info("Searching packages...")
 
-	// Search all packages (search for the 'conaninfo.txt')
-	specSearchPattern := repository + "/*/"
-	if len(referenceName) > 0 {
-		specSearchPattern = specSearchPattern + referenceName
-	} else {
-		specSearchPattern = specSearchPattern + "*"
-	}
-	specSearchPattern = specSearchPattern + "/*/*/package/*/*/conaninfo.txt"
-	pkgPattern := regexp.MustCompile(`(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)\/(?P<revision>[a-z0-9]+)\/package\/(?P<pkgId>[a-z0-9]*)\/(?P<pkgRev>[a-z0-9]+)`)
+	specSearchPattern := packagesPattern(repository, referenceName)
+	log.Debug(fmt.Sprintf("Search packages using specPattern '%s'", specSearchPattern))
 
 	params := services.NewSearchParams()
 	params.Pattern = specSearchPattern
@@ -39,23 +36,25 @@ func SearchPackages(serviceManager artifactory.ArtifactoryServicesManager, repos
 	}
 	defer reader.Close()
 
-	//
+	// Packages grouped by reference, recipe revision and package ID, with their creation time
 	allPackages := make(map[string]map[string]map[string][]types.Package)
+	created := make(map[string]time.Time)
 	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
-		m := pkgPattern.FindStringSubmatch(resultItem.Path)
-		var reference types.Reference
-		user := m[1]
-		channel := m[4]
-		if user == types.FilesystemPlaceHolder {
-			reference = types.Reference{Name: m[2], Version: m[3], User: nil, Channel: nil, Revision: m[5]}
-		} else {
-			reference = types.Reference{Name: m[2], Version: m[3], User: &user, Channel: &channel, Revision: m[5]}
+		parsed, err := parsePackagePath(resultItem.Path)
+		if err == nil && len(referenceName) > 0 && referenceName != parsed.Ref.Name {
+			err = &UnexpectedPathError{Path: resultItem.Path, Reason: fmt.Sprintf("it doesn't belong to a reference named '%s'", referenceName)}
 		}
-
-		if len(referenceName) > 0 && referenceName != reference.Name {
-			panic("Mismatch references!")
+		if err != nil {
+			if err = options.handleMalformed(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		reference := parsed.Ref
+		if versionRange != nil && !versionRange.Contains(types.ParseVersion(reference.Version)) {
+			continue
 		}
-		conanPackage := types.Package{Ref: reference, PackageId: m[6], Revision: m[7]}
+		conanPackage := *parsed
 		inner, ok := allPackages[conanPackage.Ref.RtPath(false)]
 		if !ok {
 			inner = make(map[string]map[string][]types.Package)
@@ -67,59 +66,93 @@ func SearchPackages(serviceManager artifactory.ArtifactoryServicesManager, repos
 			inner[conanPackage.Ref.Revision] = inner2
 		}
 		inner2[conanPackage.PackageId] = append(inner2[conanPackage.PackageId], conanPackage)
+		created[conanPackage.ToString(true)] = parseCreated(resultItem.Created)
 	}
 
-	// Filter recipes using 'index.json' (if onlyLatestRecipe)
+	// Keep only the packages of the latest recipe revision of each reference (if onlyLatestRecipe), it is resolved
+	// from the recipe revisions found and it may have no packages
+	resolver := NewRevisionResolver(serviceManager, repository, options)
 	filteredPackages := make(map[string]map[string][]types.Package)
 	for key, element := range allPackages {
-		if onlyLatestRecipe && len(element) > 1 {
-			rtRevisions, err := ParseRevisions(serviceManager, repository+"/"+key+"/index.json")
+		revisions := []string{}
+		for rrev := range element {
+			revisions = append(revisions, rrev)
+		}
+		if onlyLatestRecipe {
+			latestRevision, err := resolver.ReferenceRevision(anyPackage(element).Ref)
 			if err != nil {
-				return nil, err
-			}
-			latestRevision := rtRevisions[len(rtRevisions)-1]
-			for k, v := range element[latestRevision.Revision] {
-				inner, ok := filteredPackages[key+"/"+latestRevision.Revision]
-				if !ok {
-					inner = make(map[string][]types.Package)
-					filteredPackages[key+"/"+latestRevision.Revision] = inner
+				if err = options.handleMalformed(err); err != nil {
+					return nil, err
 				}
-				inner[k] = v
+				continue
 			}
-		} else {
-			for rrev, elements := range element {
-				for k, v := range elements {
-					inner, ok := filteredPackages[key+"/"+rrev]
-					if !ok {
-						inner = make(map[string][]types.Package)
-						filteredPackages[key+"/"+rrev] = inner
-					}
-					inner[k] = v
-				}
+			revisions = []string{latestRevision}
+		}
+		for _, rrev := range revisions {
+			if _, ok := element[rrev]; ok {
+				filteredPackages[key+"/"+rrev] = element[rrev]
 			}
 		}
 	}
 
-	// Filter packages using 'index.json' (if onlyLatestPackages)
+	// Keep only the latest revision of each package (if onlyLatestPackage)
 	packages := []types.Package{}
-	for key, element := range filteredPackages {
-		if onlyLatestPackage && len(element) > 1 {
-			for keyId, elementId := range element {
-				rtRevisions, err := ParseRevisions(serviceManager, repository+"/"+key+"/package/"+keyId+"/index.json")
-				if err != nil {
+	for _, element := range filteredPackages {
+		for _, elementId := range element {
+			if !onlyLatestPackage {
+				packages = append(packages, elementId...)
+				continue
+			}
+			candidates := []Candidate{}
+			for _, revision := range elementId {
+				candidates = append(candidates, Candidate{Revision: revision.Revision, Created: created[revision.ToString(true)]})
+			}
+			i, err := resolver.LatestPackage(elementId[0], candidates)
+			if err != nil {
+				if err = options.handleMalformed(err); err != nil {
 					return nil, err
 				}
-				latestRevision := rtRevisions[len(rtRevisions)-1]
-				i := Search(len(elementId), func(i int) bool {
-					return latestRevision.Revision == elementId[i].Revision
-				})
-				packages = append(packages, elementId[i])
-			}
-		} else {
-			for _, elementId := range element {
-				packages = append(packages, elementId...)
+				continue
 			}
+			packages = append(packages, elementId[i])
+		}
+	}
+	sort.Sort(types.PackagesByVersion(packages))
+	return packages, nil
+}
+
+// anyPackage returns one of the packages in `packages` (grouped by recipe revision and package ID).
+func anyPackage(packages map[string]map[string][]types.Package) types.Package {
+	for _, byPackageID := range packages {
+		for _, revisions := range byPackageID {
+			return revisions[0]
+		}
+	}
+	return types.Package{}
+}
+
+// SearchReferencePackages returns the list of packages (all package IDs and all their revisions) that belong to the
+// given reference `ref` (it must contain the revision) in the given `repository`.
+func SearchReferencePackages(serviceManager artifactory.ArtifactoryServicesManager, repository string, ref types.Reference) ([]types.Package, error) {
+	params := services.NewSearchParams()
+	params.Pattern = referencePackagesPattern(repository, ref)
+	params.Recursive = false
+	params.IncludeDirs = false
+
+	reader, err := RunSearch(serviceManager, params)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	packages := []types.Package{}
+	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+		pkg, err := parsePackagePath(resultItem.Path)
+		if err != nil || pkg.Ref.RtPath(true) != ref.RtPath(true) {
+			log.Debug(fmt.Sprintf("Path '%s' doesn't belong to reference '%s'", resultItem.Path, ref.ToString(true)))
+			continue
 		}
+		packages = append(packages, types.Package{Ref: ref, PackageId: pkg.PackageId, Revision: pkg.Revision})
 	}
 	return packages, nil
 }
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"encoding/json"
 	"io"
 	"io/ioutil"
 
@@ -12,8 +13,10 @@ import (
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/content"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,12 +24,29 @@ type MockRtServicesManagerPackages struct {
 	artifactory.EmptyArtifactoryServicesManager
 }
 
+// SearchFiles returns the packages or, when searching the recipe revisions of a reference, its revisions found in the
+// references test data.
 func (esm *MockRtServicesManagerPackages) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
 	wd, _ := os.Getwd()
 	filePath := filepath.Join(wd, "testdata/search_packages.json")
+	fileContent, _ := ioutil.ReadFile(filePath)
+	if strings.HasSuffix(params.Pattern, "/export/conanfile.py") {
+		fileContent, _ = ioutil.ReadFile(filepath.Join(wd, "testdata/search_references.json"))
+		var results struct {
+			Results []servicesUtils.ResultItem `json:"results"`
+		}
+		_ = json.Unmarshal(fileContent, &results)
+		prefix := strings.SplitN(params.Pattern, "*", 2)[0]
+		items := []servicesUtils.ResultItem{}
+		for _, item := range results.Results {
+			if strings.HasPrefix("repository/"+item.Path+"/", prefix) {
+				items = append(items, item)
+			}
+		}
+		fileContent, _ = json.Marshal(map[string][]servicesUtils.ResultItem{"results": items})
+	}
 
 	tmpFile, _ := ioutil.TempFile(os.TempDir(), "prefix-")
-	fileContent, _ := ioutil.ReadFile(filePath)
 	_, _ = tmpFile.Write(fileContent)
 	tmpFile.Close()
 
@@ -68,6 +88,22 @@ func (esm *MockRtServicesManagerPackages) ReadRemoteFile(readPath string) (io.Re
 				"time": "2020-08-17T15:21:01.757+0000"
 			}]
 		}`)), nil
+	} else if readPath == "repository/_/b2/4.1.0/_/index.json" {
+		return ioutil.NopCloser(strings.NewReader(`{
+			"reference": "b2/4.1.0@_/_",
+			"revisions": [{
+				"revision": "151655c3ac57c4adcc3681a2bf44e0af",
+				"time": "2020-08-17T15:20:56.984+0000"
+			}]
+		}`)), nil
+	} else if readPath == "repository/_/b2/4.3.0/_/index.json" {
+		return ioutil.NopCloser(strings.NewReader(`{
+			"reference": "b2/4.3.0@_/_",
+			"revisions": [{
+				"revision": "ec8af29b790f5745890470ce4220ed50",
+				"time": "2020-09-16T14:10:12.131+0000"
+			}]
+		}`)), nil
 	} else if readPath == "repository/_/b2/4.3.0/_/ec8af29b790f5745890470ce4220ed50/package/46f53f156846659bf39ad6675fa0ee8156e859fe/index.json" {
 		return ioutil.NopCloser(strings.NewReader(`{
 			"packageReference": "b2/4.3.0@_/_#ec8af29b790f5745890470ce4220ed50:46f53f156846659bf39ad6675fa0ee8156e859fe",
@@ -198,14 +234,14 @@ func (esm *MockRtServicesManagerPackages) ReadRemoteFile(readPath string) (io.Re
 
 func TestSearchPackages(t *testing.T) {
 	servicesManager := MockRtServicesManagerPackages{}
-	packages, err := SearchPackages(&servicesManager, "repository", "b2", false, false)
+	packages, err := SearchPackages(&servicesManager, "repository", "b2", false, false, nil, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, 25, len(packages))
 }
 
 func TestSearchPackagesLatestRecipes(t *testing.T) {
 	servicesManager := MockRtServicesManagerPackages{}
-	packages, err := SearchPackages(&servicesManager, "repository", "b2", true, false)
+	packages, err := SearchPackages(&servicesManager, "repository", "b2", true, false, nil, nil)
 	assert.Nil(t, err)
 	sort.Slice(packages, func(i, j int) bool {
 		return packages[i].String() < packages[j].String()
@@ -235,7 +271,7 @@ func TestSearchPackagesLatestRecipes(t *testing.T) {
 
 func TestSearchPackagesLatestAll(t *testing.T) {
 	servicesManager := MockRtServicesManagerPackages{}
-	packages, err := SearchPackages(&servicesManager, "repository", "b2", true, true)
+	packages, err := SearchPackages(&servicesManager, "repository", "b2", true, true, nil, nil)
 	assert.Nil(t, err)
 	sort.Slice(packages, func(i, j int) bool {
 		return packages[i].String() < packages[j].String()
@@ -261,3 +297,25 @@ func TestSearchPackagesLatestAll(t *testing.T) {
 	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:4db1be536558d833e52e862fd84d64d75c2b3656#675b3df28a8ad03689634e1b4f46187f", packages[13].String())
 	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:ca33edce272a279b24f87dc0d4cf5bbdcffbc187#2904158bb9b96db13de732f1c8ca4b64", packages[14].String())
 }
+
+func TestSearchReferencePackages(t *testing.T) {
+	servicesManager := MockRtServicesManagerPackages{}
+	reference := types.Reference{Name: "b2", Version: "4.3.0", User: nil, Channel: nil, Revision: "ec8af29b790f5745890470ce4220ed50"}
+	packages, err := SearchReferencePackages(&servicesManager, "repository", reference)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(packages))
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:46f53f156846659bf39ad6675fa0ee8156e859fe#91521b313ac2e32c6306677464116901", packages[0].String())
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:ca33edce272a279b24f87dc0d4cf5bbdcffbc187#2904158bb9b96db13de732f1c8ca4b64", packages[3].String())
+}
+
+func TestSearchPackagesVersionRange(t *testing.T) {
+	servicesManager := MockRtServicesManagerPackages{}
+	versionRange, err := types.ParseVersionRange("[~4.3]")
+	assert.Nil(t, err)
+	packages, err := SearchPackages(&servicesManager, "repository", "b2", true, true, versionRange, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(packages))
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:46f53f156846659bf39ad6675fa0ee8156e859fe#91521b313ac2e32c6306677464116901", packages[0].String())
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:4db1be536558d833e52e862fd84d64d75c2b3656#675b3df28a8ad03689634e1b4f46187f", packages[1].String())
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50:ca33edce272a279b24f87dc0d4cf5bbdcffbc187#2904158bb9b96db13de732f1c8ca4b64", packages[2].String())
+}
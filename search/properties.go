@@ -0,0 +1,238 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// ReadPackagesProperties returns the properties for each one of the `packages` in the given `repository`, in the
+// same order. Up to `threads` requests are run concurrently. It stops on the first error or when the context `ctx`
+// is cancelled, returning that error.
+func ReadPackagesProperties(ctx context.Context, serviceManager artifactory.ArtifactoryServicesManager, repository string, packages []types.Package, threads int) ([][]servicesUtils.Property, error) {
+	if threads < 1 {
+		threads = 1
+	}
+	workersCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]servicesUtils.Property, len(packages))
+	indexes := make(chan int)
+	errs := make(chan error, threads)
+	var wg sync.WaitGroup
+	for w := 0; w < threads; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if workersCtx.Err() != nil {
+					return
+				}
+				props, err := ReadPackageProperties(serviceManager, repository, packages[i])
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+				results[i] = props
+			}
+		}()
+	}
+
+feed:
+	for i := range packages {
+		select {
+		case indexes <- i:
+		case <-workersCtx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	if err, ok := <-errs; ok {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// PackageProperties contains the properties of a Conan package.
+type PackageProperties struct {
+	Package    types.Package
+	Properties []servicesUtils.Property
+}
+
+// ReferenceProperties contains the properties of a recipe revision.
+type ReferenceProperties struct {
+	Reference  types.Reference
+	Properties []servicesUtils.Property
+}
+
+// RevisionProperties contains the properties of a recipe revision and the ones of all its packages.
+type RevisionProperties struct {
+	Properties []servicesUtils.Property
+	Packages   []PackageProperties
+}
+
+type aqlResult struct {
+	Results []servicesUtils.ResultItem `json:"results"`
+}
+
+// ReadRevisionProperties returns the properties of the reference `ref` (it must contain the revision) and the ones of
+// all its packages (every package ID and package revision) in the given `repository` using a single AQL query.
+// Packages are sorted by package ID and package revision.
+func ReadRevisionProperties(serviceManager artifactory.ArtifactoryServicesManager, repository string, ref types.Reference) (*RevisionProperties, error) {
+	referencePath := ref.RtPath(false)
+	packagesPath := ref.RtPath(true) + "/package"
+	query := fmt.Sprintf(`items.find({"repo":%s,"type":"folder","$or":[{"path":%s,"name":%s},{"path":{"$match":%s}}]}).include("repo","path","name","type","property")`,
+		strconv.Quote(repository), strconv.Quote(referencePath), strconv.Quote(ref.Revision), strconv.Quote(packagesPath+"/*"))
+	log.Debug(fmt.Sprintf("Read revision properties using AQL '%s'", query))
+
+	stream, err := serviceManager.Aql(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	content, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	var result aqlResult
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	revisionProperties := &RevisionProperties{}
+	found := false
+	for _, item := range result.Results {
+		if item.Path == referencePath && item.Name == ref.Revision {
+			revisionProperties.Properties = item.Properties
+			found = true
+			continue
+		}
+		// Only folders 'package/<pkgId>/<pkgRev>' are package revisions
+		pkgID := strings.TrimPrefix(item.Path, packagesPath+"/")
+		if pkgID == item.Path || len(pkgID) == 0 || strings.Contains(pkgID, "/") {
+			continue
+		}
+		pkg := types.Package{Ref: ref, PackageId: pkgID, Revision: item.Name}
+		revisionProperties.Packages = append(revisionProperties.Packages, PackageProperties{Package: pkg, Properties: item.Properties})
+	}
+	if !found {
+		return nil, fmt.Errorf("Properties for reference '%s' not found", ref.RtPath(true))
+	}
+
+	sort.Slice(revisionProperties.Packages, func(i, j int) bool {
+		pi := revisionProperties.Packages[i].Package
+		pj := revisionProperties.Packages[j].Package
+		if pi.PackageId != pj.PackageId {
+			return pi.PackageId < pj.PackageId
+		}
+		return pi.Revision < pj.Revision
+	})
+	return revisionProperties, nil
+}
+
+var packageFolderPattern = regexp.MustCompile(`^(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)\/(?P<revision>[a-z0-9]+)\/package\/(?P<pkgId>[a-z0-9]+)$`)
+
+// ReadRepositoryPackagesProperties returns the properties of all the packages (every package ID and package revision)
+// matching the `referenceName` (all of them if empty) in the given `repository` using a single AQL query. Packages are
+// sorted by reference, package ID and package revision.
+func ReadRepositoryPackagesProperties(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string) ([]PackageProperties, error) {
+	name := "*"
+	if len(referenceName) > 0 {
+		name = referenceName
+	}
+	query := fmt.Sprintf(`items.find({"repo":%s,"type":"folder","path":{"$match":%s}}).include("repo","path","name","type","property")`,
+		strconv.Quote(repository), strconv.Quote("*/"+name+"/*/*/*/package/*"))
+	log.Debug(fmt.Sprintf("Read packages properties using AQL '%s'", query))
+
+	stream, err := serviceManager.Aql(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	content, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	var result aqlResult
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	packages := []PackageProperties{}
+	for _, item := range result.Results {
+		m := packageFolderPattern.FindStringSubmatch(item.Path)
+		if m == nil {
+			log.Debug(fmt.Sprintf("Folder '%s/%s' is not a package revision", item.Path, item.Name))
+			continue
+		}
+		reference := types.NewReference(m[2], m[3], m[1], m[4], m[5])
+		pkg := types.Package{Ref: reference, PackageId: m[6], Revision: item.Name}
+		packages = append(packages, PackageProperties{Package: pkg, Properties: item.Properties})
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return types.PackagesByVersion{packages[i].Package, packages[j].Package}.Less(0, 1)
+	})
+	return packages, nil
+}
+
+var referenceFolderPattern = regexp.MustCompile(`^(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)$`)
+var revisionFolderPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// ReadRepositoryReferencesProperties returns the properties of all the recipe revisions matching the `referenceName`
+// (all of them if empty) in the given `repository` using a single AQL query. References are sorted by name and version.
+func ReadRepositoryReferencesProperties(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string) ([]ReferenceProperties, error) {
+	name := "*"
+	if len(referenceName) > 0 {
+		name = referenceName
+	}
+	query := fmt.Sprintf(`items.find({"repo":%s,"type":"folder","path":{"$match":%s}}).include("repo","path","name","type","property")`,
+		strconv.Quote(repository), strconv.Quote("*/"+name+"/*/*"))
+	log.Debug(fmt.Sprintf("Read references properties using AQL '%s'", query))
+
+	stream, err := serviceManager.Aql(query)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	content, err := ioutil.ReadAll(stream)
+	if err != nil {
+		return nil, err
+	}
+	var result aqlResult
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	references := []ReferenceProperties{}
+	for _, item := range result.Results {
+		m := referenceFolderPattern.FindStringSubmatch(item.Path)
+		if m == nil || !revisionFolderPattern.MatchString(item.Name) {
+			log.Debug(fmt.Sprintf("Folder '%s/%s' is not a recipe revision", item.Path, item.Name))
+			continue
+		}
+		reference := types.NewReference(m[2], m[3], m[1], m[4], item.Name)
+		references = append(references, ReferenceProperties{Reference: reference, Properties: item.Properties})
+	}
+	sort.SliceStable(references, func(i, j int) bool {
+		return types.ByVersion{references[i].Reference, references[j].Reference}.Less(0, 1)
+	})
+	return references, nil
+}
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPackagesProperties(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	reference := types.Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
+	packages := []types.Package{}
+	for i := 0; i < 10; i++ {
+		packages = append(packages, types.Package{Ref: reference, PackageId: "pkgID", Revision: "prev"})
+	}
+
+	for _, threads := range []int{0, 1, 4, 20} {
+		props, err := ReadPackagesProperties(context.Background(), &servicesManager, "repository", packages, threads)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, len(props))
+		for i := range props {
+			assert.Equal(t, 17, len(props[i]))
+			assert.Equal(t, "license", props[i][0].Key)
+		}
+	}
+}
+
+func TestReadPackagesPropertiesError(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	reference := types.Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
+	packages := []types.Package{}
+	for i := 0; i < 10; i++ {
+		packages = append(packages, types.Package{Ref: reference, PackageId: "pkgID", Revision: "prev"})
+	}
+	packages[5].PackageId = "otherID"
+
+	props, err := ReadPackagesProperties(context.Background(), &servicesManager, "repository", packages, 4)
+	assert.Nil(t, props)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Properties for package '_/name/version/_/rrev/package/otherID/prev' not found", err.Error())
+}
+
+func TestReadPackagesPropertiesCancelled(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	reference := types.Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
+	packages := []types.Package{{Ref: reference, PackageId: "pkgID", Revision: "prev"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	props, err := ReadPackagesProperties(ctx, &servicesManager, "repository", packages, 4)
+	assert.Nil(t, props)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestReadRevisionProperties(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	reference := types.Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
+	revisionProperties, err := ReadRevisionProperties(&servicesManager, "repository", reference)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(revisionProperties.Properties))
+	assert.Equal(t, "topics", revisionProperties.Properties[0].Key)
+
+	assert.Equal(t, 3, len(revisionProperties.Packages))
+	assert.Equal(t, "name/version#rrev:pkgID1#prev1", revisionProperties.Packages[0].Package.String())
+	assert.Equal(t, 1, len(revisionProperties.Packages[0].Properties))
+	assert.Equal(t, "name/version#rrev:pkgID1#prev2", revisionProperties.Packages[1].Package.String())
+	assert.Equal(t, 2, len(revisionProperties.Packages[1].Properties))
+	assert.Equal(t, "name/version#rrev:pkgID2#prev", revisionProperties.Packages[2].Package.String())
+	assert.Equal(t, "os=Windows", revisionProperties.Packages[2].Properties[0].Value)
+
+	otherRef := types.Reference{Name: "other", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
+	revisionProperties, err = ReadRevisionProperties(&servicesManager, "repository", otherRef)
+	assert.Nil(t, revisionProperties)
+	assert.NotNil(t, err)
+	assert.Equal(t, "Properties for reference '_/other/version/_/rrev' not found", err.Error())
+}
+
+func TestReadRepositoryPackagesProperties(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	packagesProperties, err := ReadRepositoryPackagesProperties(&servicesManager, "repository", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(packagesProperties))
+	assert.Equal(t, "name/version#rrev:pkgid1#prev", packagesProperties[0].Package.String())
+	assert.Equal(t, "os=Linux", packagesProperties[0].Properties[0].Value)
+	assert.Equal(t, "name/version#rrev:pkgid2#prev", packagesProperties[1].Package.String())
+	assert.Equal(t, "name/version@user/channel#rrev:pkgid1#prev", packagesProperties[2].Package.String())
+	assert.Equal(t, 2, len(packagesProperties[2].Properties))
+
+	packagesProperties, err = ReadRepositoryPackagesProperties(&servicesManager, "repository", "other")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(packagesProperties))
+}
+
+func TestReadRepositoryReferencesProperties(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	referencesProperties, err := ReadRepositoryReferencesProperties(&servicesManager, "repository", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(referencesProperties))
+	assert.Equal(t, "name/version#rrev1", referencesProperties[0].Reference.String())
+	assert.Equal(t, "zlib/1.2.11", referencesProperties[0].Properties[0].Value)
+	assert.Equal(t, "name/version#rrev2", referencesProperties[1].Reference.String())
+	assert.Equal(t, "name/version@user/channel#rrev", referencesProperties[2].Reference.String())
+	assert.Equal(t, 0, len(referencesProperties[2].Properties))
+
+	referencesProperties, err = ReadRepositoryReferencesProperties(&servicesManager, "repository", "other")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(referencesProperties))
+}
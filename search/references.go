@@ -2,7 +2,7 @@ package search
 
 import (
 	"fmt"
-	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/jgsogo/jcli-conan-center/types"
@@ -13,21 +13,15 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-// SearchReferences returns a list of references matching the `referenceName` in the given `repository`. Use the argument
-// `onlyLatest` to retrieve only the latest revision for each reference.
-func SearchReferences(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, onlyLatest bool) ([]types.Reference, error) {
+// SearchReferences returns a list of references matching the `referenceName` in the given `repository`, sorted by
+// name and version. Use the argument `onlyLatest` to retrieve only the latest revision for each reference and
+// `versionRange` (if not nil) to retrieve only the references whose version satisfies it. Malformed entries and latest
+// revisions are handled according to the `options` (nil for the defaults).
+func SearchReferences(serviceManager artifactory.ArtifactoryServicesManager, repository string, referenceName string, onlyLatest bool, versionRange *types.VersionRange, options *Options) ([]types.Reference, error) {
 	log.Info("Searching references...")
 
-	// Search all references (search for the 'conanfile.py')
-	specSearchPattern := repository + "/*/"
-	if len(referenceName) > 0 {
-		specSearchPattern = specSearchPattern + referenceName
-	} else {
-		specSearchPattern = specSearchPattern + "*"
-	}
-	specSearchPattern = specSearchPattern + "/*/*/conanfile.py"
+	specSearchPattern := referencesPattern(repository, referenceName)
 	log.Debug(fmt.Sprintf("Search references using specPattern '%s'", specSearchPattern))
-	referencePattern := regexp.MustCompile(`(?P<user>` + types.ValidConanChars + `*)\/(?P<name>` + types.ValidConanChars + `+)\/(?P<version>` + types.ValidConanChars + `+)\/(?P<channel>` + types.ValidConanChars + `*)\/(?P<revision>[a-z0-9]+)\/export`)
 
 	params := services.NewSearchParams()
 	params.Pattern = specSearchPattern
@@ -41,34 +35,41 @@ func SearchReferences(serviceManager artifactory.ArtifactoryServicesManager, rep
 	defer reader.Close()
 
 	references := make(map[string][]types.Reference)
+	candidates := make(map[string][]Candidate)
 	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
-		m := referencePattern.FindStringSubmatch(resultItem.Path)
-		var reference types.Reference
-		user := m[1]
-		channel := m[4]
-		if user == types.FilesystemPlaceHolder {
-			reference = types.Reference{Name: m[2], Version: m[3], User: nil, Channel: nil, Revision: m[5]}
-		} else {
-			reference = types.Reference{Name: m[2], Version: m[3], User: &user, Channel: &channel, Revision: m[5]}
+		parsed, err := parseReferencePath(resultItem.Path)
+		if err != nil {
+			if err = options.handleMalformed(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		reference := *parsed
+		if versionRange != nil && !versionRange.Contains(types.ParseVersion(reference.Version)) {
+			continue
 		}
 		references[reference.ToString(false)] = append(references[reference.ToString(false)], reference)
+		candidates[reference.ToString(false)] = append(candidates[reference.ToString(false)], Candidate{Revision: reference.Revision, Created: parseCreated(resultItem.Created)})
 	}
 
-	// Filter duplicated references using 'index.json' (if onlyLatest)
+	// Keep only the latest revision of each reference (if onlyLatest)
+	resolver := NewRevisionResolver(serviceManager, repository, options)
 	retReferences := []types.Reference{}
-	for _, element := range references {
-		if onlyLatest && len(element) > 1 {
-			rtRevisions, err := ParseRevisions(serviceManager, repository+"/"+element[0].RtPath(false)+"/index.json")
-			if err != nil {
+	for key, element := range references {
+		if !onlyLatest {
+			retReferences = append(retReferences, element...)
+			continue
+		}
+		i, err := resolver.LatestReference(element[0], candidates[key])
+		if err != nil {
+			if err = options.handleMalformed(err); err != nil {
 				return nil, err
 			}
-			latestRevision := rtRevisions[len(rtRevisions)-1]
-			i := Search(len(element), func(i int) bool { return latestRevision.Revision == element[i].Revision })
-			retReferences = append(retReferences, element[i])
-		} else {
-			retReferences = append(retReferences, element...)
+			continue
 		}
+		retReferences = append(retReferences, element[i])
 	}
+	sort.Sort(types.ByVersion(retReferences))
 	log.Info("Found", strconv.Itoa(len(retReferences)), "references.")
 	return retReferences, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jgsogo/jcli-conan-center/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,20 +72,36 @@ func (esm *MockRtServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser
 				"time": "2020-08-17T15:21:01.757+0000"
 			}]
 		}`)), nil
+	} else if readPath == "repository/_/b2/4.1.0/_/index.json" {
+		return ioutil.NopCloser(strings.NewReader(`{
+			"reference": "b2/4.1.0@_/_",
+			"revisions": [{
+				"revision": "151655c3ac57c4adcc3681a2bf44e0af",
+				"time": "2020-08-17T15:20:56.984+0000"
+			}]
+		}`)), nil
+	} else if readPath == "repository/_/b2/4.3.0/_/index.json" {
+		return ioutil.NopCloser(strings.NewReader(`{
+			"reference": "b2/4.3.0@_/_",
+			"revisions": [{
+				"revision": "ec8af29b790f5745890470ce4220ed50",
+				"time": "2020-09-16T14:10:12.131+0000"
+			}]
+		}`)), nil
 	}
 	return nil, nil
 }
 
 func TestSearchReferences(t *testing.T) {
 	servicesManager := MockRtServicesManager{}
-	references, err := SearchReferences(&servicesManager, "repository", "name/version", false)
+	references, err := SearchReferences(&servicesManager, "repository", "name/version", false, nil, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, 8, len(references))
 }
 
 func TestSearchReferencesLatest(t *testing.T) {
 	servicesManager := MockRtServicesManager{}
-	references, err := SearchReferences(&servicesManager, "repository", "name/version", true)
+	references, err := SearchReferences(&servicesManager, "repository", "name/version", true, nil, nil)
 	assert.Nil(t, err)
 	sort.Slice(references, func(i, j int) bool {
 		return references[i].String() < references[j].String()
@@ -96,3 +113,27 @@ func TestSearchReferencesLatest(t *testing.T) {
 	assert.Equal(t, "b2/4.2.0#efacbfac6ee3561ff07968a372b940af", references[3].String())
 	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50", references[4].String())
 }
+
+func TestSearchReferencesSorted(t *testing.T) {
+	servicesManager := MockRtServicesManager{}
+	references, err := SearchReferences(&servicesManager, "repository", "b2", true, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(references))
+	assert.Equal(t, "b2/4.0.0#3c07b6a54477e856d429493d01c85636", references[0].String())
+	assert.Equal(t, "b2/4.0.1#fe103dcc7b9fa2226d82f5fb43af1d09", references[1].String())
+	assert.Equal(t, "b2/4.1.0#151655c3ac57c4adcc3681a2bf44e0af", references[2].String())
+	assert.Equal(t, "b2/4.2.0#efacbfac6ee3561ff07968a372b940af", references[3].String())
+	assert.Equal(t, "b2/4.3.0#ec8af29b790f5745890470ce4220ed50", references[4].String())
+}
+
+func TestSearchReferencesVersionRange(t *testing.T) {
+	servicesManager := MockRtServicesManager{}
+	versionRange, err := types.ParseVersionRange("[>=4.0.1 <4.3]")
+	assert.Nil(t, err)
+	references, err := SearchReferences(&servicesManager, "repository", "b2", true, versionRange, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(references))
+	assert.Equal(t, "b2/4.0.1#fe103dcc7b9fa2226d82f5fb43af1d09", references[0].String())
+	assert.Equal(t, "b2/4.1.0#151655c3ac57c4adcc3681a2bf44e0af", references[1].String())
+	assert.Equal(t, "b2/4.2.0#efacbfac6ee3561ff07968a372b940af", references[2].String())
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// RevisionSource defines where the times used to resolve the latest revision are taken from.
+type RevisionSource string
+
+const (
+	// IndexSource uses the order of the revisions listed in the 'index.json' files.
+	IndexSource RevisionSource = "index"
+	// CreatedSource uses the time the revisions were created in Artifactory, 'index.json' files are not read.
+	CreatedSource RevisionSource = "created"
+)
+
+// ParseRevisionSource returns the revision source with the given name ('index' or 'created').
+func ParseRevisionSource(name string) (RevisionSource, error) {
+	for _, source := range []RevisionSource{IndexSource, CreatedSource} {
+		if string(source) == strings.ToLower(name) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid revision source '%s'. Expected one of: index, created", name)
+}
+
+// RevisionResolver resolves the latest revision of the references and packages in a repository. All the search
+// functions and commands use it, so the latest revision is the same whatever the number of revisions found.
+type RevisionResolver struct {
+	serviceManager artifactory.ArtifactoryServicesManager
+	repository     string
+	options        *Options
+}
+
+// NewRevisionResolver returns a resolver for the `repository` using the revision source and the fallback policy of
+// the `options` (nil for the defaults).
+func NewRevisionResolver(serviceManager artifactory.ArtifactoryServicesManager, repository string, options *Options) *RevisionResolver {
+	return &RevisionResolver{serviceManager: serviceManager, repository: repository, options: options}
+}
+
+// LatestReference returns the position in `candidates` of the latest recipe revision of `ref` (its revision is
+// ignored).
+func (resolver *RevisionResolver) LatestReference(ref types.Reference, candidates []Candidate) (int, error) {
+	latest, _, err := resolver.resolve(ref.ToString(false), resolver.repository+"/"+ref.RtPath(false)+"/index.json", candidates)
+	return latest, err
+}
+
+// LatestPackage returns the position in `candidates` of the latest package revision of `pkg` (its package revision
+// is ignored).
+func (resolver *RevisionResolver) LatestPackage(pkg types.Package, candidates []Candidate) (int, error) {
+	pkg.Revision = ""
+	latest, _, err := resolver.resolve(pkg.ToString(true), resolver.repository+"/"+pkg.RtPath(false)+"/index.json", candidates)
+	return latest, err
+}
+
+// ReferenceRevisions returns the recipe revisions of `ref` found in the repository (its revision is ignored) sorted
+// by time, the latest one last. Revisions listed in the 'index.json' file without a folder are not returned.
+func (resolver *RevisionResolver) ReferenceRevisions(ref types.Reference) ([]types.RtRevisionsData, error) {
+	name := ref.ToString(false)
+	candidates, err := resolver.candidates(referenceRevisionsPattern(resolver.repository, ref), func(path string) (string, error) {
+		parsed, err := parseReferencePath(path)
+		if err != nil {
+			return "", err
+		}
+		if parsed.ToString(false) != name {
+			return "", &UnexpectedPathError{Path: path, Reason: fmt.Sprintf("not a recipe revision of '%s'", name)}
+		}
+		return parsed.Revision, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolver.revisions(name, resolver.repository+"/"+ref.RtPath(false)+"/index.json", candidates)
+}
+
+// PackageRevisions returns the package revisions of `pkg` found in the repository (its package revision is ignored)
+// sorted by time, the latest one last. Revisions listed in the 'index.json' file without a folder are not returned.
+func (resolver *RevisionResolver) PackageRevisions(pkg types.Package) ([]types.RtRevisionsData, error) {
+	pkg.Revision = ""
+	name := pkg.ToString(true)
+	candidates, err := resolver.candidates(packageRevisionsPattern(resolver.repository, pkg), func(path string) (string, error) {
+		parsed, err := parsePackagePath(path)
+		if err != nil {
+			return "", err
+		}
+		parsedRevision := parsed.Revision
+		parsed.Revision = ""
+		if parsed.ToString(true) != name {
+			return "", &UnexpectedPathError{Path: path, Reason: fmt.Sprintf("not a package revision of '%s'", name)}
+		}
+		return parsedRevision, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolver.revisions(name, resolver.repository+"/"+pkg.RtPath(false)+"/index.json", candidates)
+}
+
+// ReferenceRevision returns the latest recipe revision of `ref` found in the repository (its revision is ignored).
+func (resolver *RevisionResolver) ReferenceRevision(ref types.Reference) (string, error) {
+	revisions, err := resolver.ReferenceRevisions(ref)
+	if err != nil {
+		return "", err
+	}
+	return revisions[len(revisions)-1].Revision, nil
+}
+
+// PackageRevision returns the latest package revision of `pkg` found in the repository (its package revision is
+// ignored).
+func (resolver *RevisionResolver) PackageRevision(pkg types.Package) (string, error) {
+	revisions, err := resolver.PackageRevisions(pkg)
+	if err != nil {
+		return "", err
+	}
+	return revisions[len(revisions)-1].Revision, nil
+}
+
+// SelectReferenceRevisions returns the recipe revisions of `ref` found in the repository chosen by the `selector`
+// (the revision of `ref` is ignored).
+func (resolver *RevisionResolver) SelectReferenceRevisions(ref types.Reference, selector *types.RevisionSelector) ([]types.Reference, error) {
+	revisions, err := resolver.ReferenceRevisions(ref)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := selector.Select(revisions)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot resolve '%s%s': %s", ref.ToString(false), selector, err)
+	}
+	references := []types.Reference{}
+	for _, rtRevision := range selected {
+		ref.Revision = rtRevision.Revision
+		references = append(references, ref)
+	}
+	return references, nil
+}
+
+// candidates returns the revisions found searching the anchor files matching the `pattern`, `parse` returns the
+// revision for the path of each file. Paths that cannot be parsed are handled as malformed entries.
+func (resolver *RevisionResolver) candidates(pattern string, parse func(path string) (string, error)) ([]Candidate, error) {
+	params := services.NewSearchParams()
+	params.Pattern = pattern
+	params.Recursive = false
+	params.IncludeDirs = false
+
+	reader, err := RunSearch(resolver.serviceManager, params)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	candidates := []Candidate{}
+	for resultItem := new(servicesUtils.ResultItem); reader.NextRecord(resultItem) == nil; resultItem = new(servicesUtils.ResultItem) {
+		revision, err := parse(resultItem.Path)
+		if err != nil {
+			if err = resolver.options.handleMalformed(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		candidates = append(candidates, Candidate{Revision: revision, Created: parseCreated(resultItem.Created)})
+	}
+	return candidates, nil
+}
+
+// revisions returns the `candidates` of the reference or package `name` sorted by time, the latest one last.
+func (resolver *RevisionResolver) revisions(name string, indexPath string, candidates []Candidate) ([]types.RtRevisionsData, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("No revisions of '%s' found in the repository", name)
+	}
+	latest, times, err := resolver.resolve(name, indexPath, candidates)
+	if err != nil {
+		return nil, err
+	}
+	revisions := []types.RtRevisionsData{}
+	for i, candidate := range candidates {
+		if i != latest {
+			revisions = append(revisions, types.RtRevisionsData{Revision: candidate.Revision, Time: types.RtTimestamp{Time: times[i]}})
+		}
+	}
+	sort.Stable(types.ByTime(revisions))
+	return append(revisions, types.RtRevisionsData{Revision: candidates[latest].Revision, Time: types.RtTimestamp{Time: times[latest]}}), nil
+}
+
+// resolve returns the position in `candidates` of the latest revision of the reference or package `name` and the
+// time of each candidate: the one listed in the 'index.json' file `indexPath` or, using `CreatedSource` or for
+// revisions not listed, the time it was created in Artifactory.
+func (resolver *RevisionResolver) resolve(name string, indexPath string, candidates []Candidate) (int, []time.Time, error) {
+	if resolver.options.source() == CreatedSource {
+		times := []time.Time{}
+		for _, candidate := range candidates {
+			times = append(times, candidate.Created)
+		}
+		return newestTime(times), times, nil
+	}
+	rtRevisions, err := listedRevisions(resolver.serviceManager, indexPath)
+	if err != nil {
+		return -1, nil, err
+	}
+	latest, err := resolveLatest(name, rtRevisions, candidates, resolver.options.fallback())
+	if err != nil {
+		return -1, nil, err
+	}
+	return latest, revisionTimes(rtRevisions, candidates), nil
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRevisionSource(t *testing.T) {
+	source, err := ParseRevisionSource("Created")
+	assert.Nil(t, err)
+	assert.Equal(t, CreatedSource, source)
+
+	_, err = ParseRevisionSource("modified")
+	assert.Equal(t, "Invalid revision source 'modified'. Expected one of: index, created", err.Error())
+}
+
+func TestRevisionResolver(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	serviceManager := newMalformedServicesManager()
+	ref := types.NewReference("b2", "4.0.0", "_", "_", "")
+	pkg := types.Package{Ref: types.NewReference("b2", "4.0.0", "_", "_", "rrev1"), PackageId: "pkgid"}
+
+	// A single candidate is checked against the 'index.json' too
+	resolver := NewRevisionResolver(serviceManager, "repository", nil)
+	i, err := resolver.LatestReference(ref, []Candidate{{"rrev1", day(1)}, {"rrev2", day(2)}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	i, err = resolver.LatestReference(ref, []Candidate{{"rrev1", day(1)}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+	_, err = resolver.LatestPackage(pkg, []Candidate{{"prev1", day(1)}})
+	assert.Equal(t, &MissingIndexError{Path: "repository/_/b2/4.0.0/_/rrev1/package/pkgid/index.json"}, err)
+
+	revision, err := resolver.ReferenceRevision(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, "rrev1", revision)
+	_, err = resolver.PackageRevision(pkg)
+	assert.NotNil(t, err)
+
+	// Creation time in Artifactory, 'index.json' files are not read
+	resolver = NewRevisionResolver(serviceManager, "repository", &Options{Source: CreatedSource})
+	i, err = resolver.LatestReference(ref, []Candidate{{"rrev1", day(1)}, {"rrev2", day(2)}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, i)
+	i, err = resolver.LatestPackage(pkg, []Candidate{{"prev1", day(1)}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, i)
+}
+
+func TestSearchLatestConsistent(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.0/_/rrev1/package/pkgid/index.json"] = `{"revisions": [
+		{"revision": "prev1", "time": "2021-03-01T00:00:00.000+0000"}]}`
+	serviceManager.files = []servicesUtils.ResultItem{
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/export", Name: "conanfile.py", Created: "2021-03-01T00:00:00.000Z"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev1", Name: "conaninfo.txt", Created: "2021-03-01T00:00:00.000Z"},
+		{Repo: "repository", Path: "_/b2/4.0.0/_/rrev1/package/pkgid/prev2", Name: "conaninfo.txt", Created: "2021-03-02T00:00:00.000Z"},
+	}
+
+	// A single recipe revision and package ID, the latest package revision is the one listed
+	packages, err := SearchPackages(serviceManager, "repository", "b2", true, true, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(packages))
+	assert.Equal(t, "b2/4.0.0#rrev1:pkgid#prev1", packages[0].String())
+
+	packages, err = SearchPackages(serviceManager, "repository", "b2", true, true, nil, &Options{Source: CreatedSource})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(packages))
+	assert.Equal(t, "b2/4.0.0#rrev1:pkgid#prev2", packages[0].String())
+}
+
+func TestRevisionResolverRevisions(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.1/_/index.json"] = `{"revisions": [
+		{"revision": "rrev2", "time": "2020-08-01T00:00:00.000+0000"},
+		{"revision": "rrev3", "time": "2021-03-03T00:00:00.000+0000"}]}`
+
+	// 'rrev3' is not found, 'rrev1' is not listed but it was created after 'rrev2'
+	ref := types.NewReference("b2", "4.0.1", "_", "_", "rrev")
+	revisions, err := NewRevisionResolver(serviceManager, "repository", nil).ReferenceRevisions(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(revisions))
+	assert.Equal(t, "rrev2", revisions[0].Revision)
+	assert.Equal(t, "rrev1", revisions[1].Revision)
+
+	_, err = NewRevisionResolver(serviceManager, "repository", &Options{Fallback: FallbackNone}).ReferenceRevisions(ref)
+	assert.Equal(t, &RevisionNotFoundError{Reference: "b2/4.0.1", Revision: "rrev3"}, err)
+
+	pkg := types.Package{Ref: types.NewReference("b2", "4.0.0", "_", "_", "rrev1"), PackageId: "pkgid2"}
+	_, err = NewRevisionResolver(serviceManager, "repository", nil).PackageRevisions(pkg)
+	assert.Equal(t, "Cannot read revisions from 'repository/_/b2/4.0.0/_/rrev1/package/pkgid2/index.json': 404 Not Found", err.Error())
+	serviceManager.indexes["repository/_/b2/4.0.0/_/rrev1/package/pkgid2/index.json"] = `{"revisions": [
+		{"revision": "prev1", "time": "2021-03-01T00:00:00.000+0000"}]}`
+	revisions, err = NewRevisionResolver(serviceManager, "repository", nil).PackageRevisions(pkg)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(revisions))
+	assert.Equal(t, "prev1", revisions[0].Revision)
+
+	_, err = NewRevisionResolver(serviceManager, "repository", nil).ReferenceRevisions(types.NewReference("b2", "4.0.3", "_", "_", ""))
+	assert.Equal(t, "No revisions of 'b2/4.0.3' found in the repository", err.Error())
+}
+
+func TestSelectReferenceRevisions(t *testing.T) {
+	serviceManager := newMalformedServicesManager()
+	serviceManager.indexes["repository/_/b2/4.0.0/_/index.json"] = `{"revisions": [
+		{"revision": "rrev2", "time": "2021-03-03T00:00:00.000+0000"},
+		{"revision": "rrev1", "time": "2021-03-01T00:00:00.000+0000"}]}`
+	resolver := NewRevisionResolver(serviceManager, "repository", nil)
+
+	ref, selector, _ := types.ParseReferenceSelector("b2/4.0.0@2021-03-02")
+	references, err := resolver.SelectReferenceRevisions(*ref, selector)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(references))
+	assert.Equal(t, "b2/4.0.0#rrev1", references[0].String())
+
+	ref, selector, _ = types.ParseReferenceSelector("b2/4.0.0#all")
+	references, err = resolver.SelectReferenceRevisions(*ref, selector)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(references))
+	assert.Equal(t, "b2/4.0.0#rrev2", references[1].String())
+
+	ref, selector, _ = types.ParseReferenceSelector("b2/4.0.0#latest~2")
+	_, err = resolver.SelectReferenceRevisions(*ref, selector)
+	assert.Equal(t, "Cannot resolve 'b2/4.0.0#latest~2': Cannot select '#latest~2', there are only 2 revisions", err.Error())
+
+	// Only the revisions found are selected, 'rrev3' is listed but its folder is missing
+	ref, selector, _ = types.ParseReferenceSelector("b2/4.0.1#all")
+	references, err = resolver.SelectReferenceRevisions(*ref, selector)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(references))
+	assert.Equal(t, "b2/4.0.1#rrev2", references[0].String())
+	assert.Equal(t, "b2/4.0.1#rrev1", references[1].String())
+}
@@ -11,6 +11,7 @@ import (
 	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/content"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jgsogo/jcli-conan-center/conaninfo"
 	"github.com/jgsogo/jcli-conan-center/types"
 )
 
@@ -24,8 +25,8 @@ func Search(length int, f func(index int) bool) int {
 	return -1
 }
 
-// ParseRevisions parses and 'index.json' file stored in Artifactory and returns a sorted list of revisions.
-func ParseRevisions(serviceManager artifactory.ArtifactoryServicesManager, indexPath string) ([]types.RtRevisionsData, error) {
+// ReadIndexJSON reads an 'index.json' file stored in Artifactory and returns its contents (revisions are not sorted).
+func ReadIndexJSON(serviceManager artifactory.ArtifactoryServicesManager, indexPath string) (*types.RtIndexJSON, error) {
 	ioReaderCloser, err := serviceManager.ReadRemoteFile(indexPath)
 	if err != nil {
 		return nil, err
@@ -35,13 +36,50 @@ func ParseRevisions(serviceManager artifactory.ArtifactoryServicesManager, index
 	if err != nil {
 		return nil, err
 	}
-	var revisions types.RtIndexJSON
-	err = json.Unmarshal(content, &revisions)
+	var index types.RtIndexJSON
+	err = json.Unmarshal(content, &index)
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// ReadConanInfo reads the 'conaninfo.txt' file of the package `pkg` (it must contain the revisions) stored in the
+// given `repository` and returns its contents.
+func ReadConanInfo(serviceManager artifactory.ArtifactoryServicesManager, repository string, pkg types.Package) (*conaninfo.ConanInfo, error) {
+	ioReaderCloser, err := serviceManager.ReadRemoteFile(repository + "/" + pkg.RtPath(true) + "/conaninfo.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer ioReaderCloser.Close()
+	info, err := conaninfo.Parse(ioReaderCloser)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse 'conaninfo.txt' for package '%s': %s", pkg.ToString(true), err)
+	}
+	return info, nil
+}
+
+// ParseRevisions parses and 'index.json' file stored in Artifactory and returns a sorted list of revisions.
+func ParseRevisions(serviceManager artifactory.ArtifactoryServicesManager, indexPath string) ([]types.RtRevisionsData, error) {
+	index, err := ReadIndexJSON(serviceManager, indexPath)
+	if err != nil {
+		return nil, &MissingIndexError{Path: indexPath, Err: err}
+	}
+	sort.Sort(types.ByTime(index.Revisions))
+	return index.Revisions, nil
+}
+
+// listedRevisions returns the revisions listed in the 'index.json' file `indexPath` sorted by time. It fails with a
+// `MissingIndexError` if the file cannot be read or it is empty.
+func listedRevisions(serviceManager artifactory.ArtifactoryServicesManager, indexPath string) ([]types.RtRevisionsData, error) {
+	rtRevisions, err := ParseRevisions(serviceManager, indexPath)
 	if err != nil {
 		return nil, err
 	}
-	sort.Sort(types.ByTime(revisions.Revisions))
-	return revisions.Revisions, nil
+	if len(rtRevisions) == 0 {
+		return nil, &MissingIndexError{Path: indexPath}
+	}
+	return rtRevisions, nil
 }
 
 // RunSearch return the content according to the given `searchParams`.
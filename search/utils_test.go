@@ -29,6 +29,11 @@ const (
 			"time": "2020-08-15T15:20:47.871+0000"
 		}]
 	}`
+	contentConanInfo = `[settings]
+    os=Linux
+[full_requires]
+    zlib/1.2.11:pkgid
+`
 )
 
 type MockArtifactoryServicesManager struct {
@@ -36,10 +41,34 @@ type MockArtifactoryServicesManager struct {
 }
 
 func (esm *MockArtifactoryServicesManager) ReadRemoteFile(readPath string) (io.ReadCloser, error) {
+	if strings.HasSuffix(readPath, "/conaninfo.txt") {
+		return ioutil.NopCloser(strings.NewReader(contentConanInfo)), nil
+	}
 	r := ioutil.NopCloser(strings.NewReader(contentRevisions))
 	return r, nil
 }
 
+func (esm *MockArtifactoryServicesManager) Aql(aql string) (io.ReadCloser, error) {
+	if strings.Contains(aql, `"path":"_/name/version/_","name":"rrev"`) {
+		wd, _ := os.Getwd()
+		fileContent, _ := ioutil.ReadFile(filepath.Join(wd, "testdata/aql_revision_properties.json"))
+		return ioutil.NopCloser(strings.NewReader(string(fileContent))), nil
+	}
+	if strings.Contains(aql, `"path":{"$match":"*/*/*/*/*/package/*"}`) {
+		wd, _ := os.Getwd()
+		fileContent, _ := ioutil.ReadFile(filepath.Join(wd, "testdata/aql_repository_packages.json"))
+		return ioutil.NopCloser(strings.NewReader(string(fileContent))), nil
+	}
+	if strings.Contains(aql, `"path":{"$match":"*/*/*/*"}`) {
+		wd, _ := os.Getwd()
+		fileContent, _ := ioutil.ReadFile(filepath.Join(wd, "testdata/aql_repository_references.json"))
+		return ioutil.NopCloser(strings.NewReader(string(fileContent))), nil
+	}
+	wd, _ := os.Getwd()
+	fileContent, _ := ioutil.ReadFile(filepath.Join(wd, "testdata/not_found.json"))
+	return ioutil.NopCloser(strings.NewReader(string(fileContent))), nil
+}
+
 func (esm *MockArtifactoryServicesManager) SearchFiles(params services.SearchParams) (*content.ContentReader, error) {
 	if params.Pattern == "the/pattern/to/search/for" {
 		return content.NewContentReader("filePath", "arrayKey"), nil
@@ -89,6 +118,15 @@ func TestParseRevisions(t *testing.T) {
 	assert.Equal(t, revisions[2].Revision, "3c07b6a54477e856d429493d01c85636")
 }
 
+func TestReadConanInfo(t *testing.T) {
+	servicesManager := MockArtifactoryServicesManager{}
+	ref := types.Reference{Name: "name", Version: "version", Revision: "rrev"}
+	info, err := ReadConanInfo(&servicesManager, "repository", types.Package{Ref: ref, PackageId: "pkgid", Revision: "prev"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"os": "Linux"}, info.Settings)
+	assert.Equal(t, []string{"zlib/1.2.11"}, info.References())
+}
+
 func TestRunSearch(t *testing.T) {
 	servicesManager := MockArtifactoryServicesManager{}
 	params := services.NewSearchParams()
@@ -0,0 +1,186 @@
+// Package stats contains functionality to compute statistics about the contents of a Conan repository.
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// SettingsKeys are the settings whose values distribution is reported.
+var SettingsKeys = []string{"os", "compiler", "arch"}
+
+// Counts contains the number of items of each type and the storage they use (in bytes). Timestamps of the oldest
+// and newest recipe revisions are nil if unknown.
+type Counts struct {
+	References       int        `json:"references" yaml:"references"`
+	Versions         int        `json:"versions" yaml:"versions"`
+	RecipeRevisions  int        `json:"recipe_revisions" yaml:"recipe_revisions"`
+	PackageIDs       int        `json:"package_ids" yaml:"package_ids"`
+	PackageRevisions int        `json:"package_revisions" yaml:"package_revisions"`
+	Size             int64      `json:"size" yaml:"size"`
+	OldestRevision   *time.Time `json:"oldest_revision,omitempty" yaml:"oldest_revision,omitempty"`
+	NewestRevision   *time.Time `json:"newest_revision,omitempty" yaml:"newest_revision,omitempty"`
+}
+
+// NameCounts are the `Counts` for all the references with the same name.
+type NameCounts struct {
+	Name   string `json:"name" yaml:"name"`
+	Counts `yaml:",inline"`
+}
+
+// Report contains the statistics of a repository: totals, counts per reference name (sorted by name) and the
+// number of package revisions for each value of the `SettingsKeys`.
+type Report struct {
+	Repository string                    `json:"repository" yaml:"repository"`
+	Total      Counts                    `json:"total" yaml:"total"`
+	Names      []NameCounts              `json:"names" yaml:"names"`
+	Settings   map[string]map[string]int `json:"settings" yaml:"settings"`
+}
+
+type nameData struct {
+	references       map[string]bool
+	versions         map[string]bool
+	recipeRevisions  map[string]bool
+	packageIDs       map[string]bool
+	packageRevisions map[string]bool
+	size             int64
+	oldest           *time.Time
+	newest           *time.Time
+}
+
+// Collector accumulates data about a repository to compute its `Report`. Items can be added in any order and
+// duplicated items are counted only once.
+type Collector struct {
+	repository string
+	names      map[string]*nameData
+	settings   map[string]map[string]int
+}
+
+// NewCollector returns a `Collector` for the given `repository`.
+func NewCollector(repository string) *Collector {
+	settings := make(map[string]map[string]int)
+	for _, key := range SettingsKeys {
+		settings[key] = make(map[string]int)
+	}
+	return &Collector{repository: repository, names: make(map[string]*nameData), settings: settings}
+}
+
+func (collector *Collector) name(name string) *nameData {
+	data, ok := collector.names[name]
+	if !ok {
+		data = &nameData{
+			references:       make(map[string]bool),
+			versions:         make(map[string]bool),
+			recipeRevisions:  make(map[string]bool),
+			packageIDs:       make(map[string]bool),
+			packageRevisions: make(map[string]bool),
+		}
+		collector.names[name] = data
+	}
+	return data
+}
+
+// AddReferences adds the given references (with revisions).
+func (collector *Collector) AddReferences(references []types.Reference) {
+	for i := range references {
+		ref := references[i]
+		data := collector.name(ref.Name)
+		data.references[ref.ToString(false)] = true
+		data.versions[ref.Version] = true
+		data.recipeRevisions[ref.ToString(true)] = true
+	}
+}
+
+// AddPackages adds the given packages (with revisions).
+func (collector *Collector) AddPackages(packages []types.Package) {
+	for i := range packages {
+		pkg := packages[i]
+		data := collector.name(pkg.Ref.Name)
+		data.packageIDs[pkg.Ref.ToString(true)+":"+pkg.PackageId] = true
+		data.packageRevisions[pkg.ToString(true)] = true
+	}
+}
+
+// AddFile adds the size of a file found in `path` (relative to the repository, following the Conan layout). Files
+// outside the folder of a reference ('<user>/<name>/<version>/<channel>/...') are ignored.
+func (collector *Collector) AddFile(path string, size int64) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 5 {
+		return
+	}
+	collector.name(parts[1]).size += size
+}
+
+// AddRevisions adds the timestamps of the recipe revisions of a reference with the given `name`.
+func (collector *Collector) AddRevisions(name string, revisions []types.RtRevisionsData) {
+	data := collector.name(name)
+	for _, revision := range revisions {
+		t := revision.Time.Time
+		if data.oldest == nil || t.Before(*data.oldest) {
+			data.oldest = &t
+		}
+		if data.newest == nil || t.After(*data.newest) {
+			data.newest = &t
+		}
+	}
+}
+
+// AddPackageProperties adds the `properties` of a package revision, the values of the 'settings' property are
+// used to compute the distribution of the `SettingsKeys`.
+func (collector *Collector) AddPackageProperties(properties []servicesUtils.Property) {
+	for _, prop := range properties {
+		if prop.Key != "settings" {
+			continue
+		}
+		kv := strings.SplitN(prop.Value, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if values, ok := collector.settings[kv[0]]; ok {
+			values[kv[1]]++
+		}
+	}
+}
+
+// Report returns the statistics of the data collected so far.
+func (collector *Collector) Report() *Report {
+	report := &Report{Repository: collector.repository, Names: []NameCounts{}, Settings: collector.settings}
+	names := []string{}
+	for name := range collector.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := collector.names[name]
+		counts := Counts{
+			References:       len(data.references),
+			Versions:         len(data.versions),
+			RecipeRevisions:  len(data.recipeRevisions),
+			PackageIDs:       len(data.packageIDs),
+			PackageRevisions: len(data.packageRevisions),
+			Size:             data.size,
+			OldestRevision:   data.oldest,
+			NewestRevision:   data.newest,
+		}
+		report.Names = append(report.Names, NameCounts{Name: name, Counts: counts})
+
+		report.Total.References += counts.References
+		report.Total.Versions += counts.Versions
+		report.Total.RecipeRevisions += counts.RecipeRevisions
+		report.Total.PackageIDs += counts.PackageIDs
+		report.Total.PackageRevisions += counts.PackageRevisions
+		report.Total.Size += counts.Size
+		if data.oldest != nil && (report.Total.OldestRevision == nil || data.oldest.Before(*report.Total.OldestRevision)) {
+			report.Total.OldestRevision = data.oldest
+		}
+		if data.newest != nil && (report.Total.NewestRevision == nil || data.newest.After(*report.Total.NewestRevision)) {
+			report.Total.NewestRevision = data.newest
+		}
+	}
+	return report
+}
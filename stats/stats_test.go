@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	servicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	collector := NewCollector("repo")
+	b2Rrev1 := types.NewReference("b2", "4.0.0", "_", "_", "rrev1")
+	b2Rrev2 := types.NewReference("b2", "4.0.0", "_", "_", "rrev2")
+	b2Other := types.NewReference("b2", "4.1.0", "_", "_", "rrev")
+	zlib := types.NewReference("zlib", "1.2.11", "_", "_", "rrev")
+	collector.AddReferences([]types.Reference{b2Rrev1, b2Rrev2, b2Other, zlib, zlib})
+	collector.AddPackages([]types.Package{
+		{Ref: b2Rrev1, PackageId: "pkg1", Revision: "prev1"},
+		{Ref: b2Rrev1, PackageId: "pkg1", Revision: "prev2"},
+		{Ref: b2Rrev2, PackageId: "pkg1", Revision: "prev1"},
+		{Ref: zlib, PackageId: "pkg2", Revision: "prev1"},
+	})
+	collector.AddFile("_/b2/4.0.0/_/rrev1/export/conanfile.py", 100)
+	collector.AddFile("_/b2/4.0.0/_/rrev1/package/pkg1/prev1/conan_package.tgz", 1000)
+	collector.AddFile("_/zlib/1.2.11/_/rrev/export/conanfile.py", 50)
+	collector.AddFile("index.json", 10)
+	collector.AddFile("_/other/.DS_Store", 10)
+
+	older := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	collector.AddRevisions("b2", []types.RtRevisionsData{{Revision: "rrev2", Time: types.RtTimestamp{Time: newer}}, {Revision: "rrev1", Time: types.RtTimestamp{Time: older}}})
+
+	collector.AddPackageProperties([]servicesUtils.Property{{Key: "settings", Value: "os=Linux"}, {Key: "settings", Value: "os_build=Linux"}, {Key: "settings", Value: "compiler=gcc"}, {Key: "license", Value: "MIT"}})
+	collector.AddPackageProperties([]servicesUtils.Property{{Key: "settings", Value: "os=Windows"}, {Key: "settings", Value: "compiler=Visual Studio"}, {Key: "settings", Value: "arch=x86_64"}})
+	collector.AddPackageProperties([]servicesUtils.Property{{Key: "settings", Value: "os=Linux"}})
+
+	report := collector.Report()
+	assert.Equal(t, "repo", report.Repository)
+	assert.Equal(t, 2, len(report.Names))
+
+	b2 := report.Names[0]
+	assert.Equal(t, "b2", b2.Name)
+	assert.Equal(t, 2, b2.References)
+	assert.Equal(t, 2, b2.Versions)
+	assert.Equal(t, 3, b2.RecipeRevisions)
+	assert.Equal(t, 2, b2.PackageIDs)
+	assert.Equal(t, 3, b2.PackageRevisions)
+	assert.Equal(t, int64(1100), b2.Size)
+	assert.Equal(t, older, *b2.OldestRevision)
+	assert.Equal(t, newer, *b2.NewestRevision)
+
+	assert.Equal(t, "zlib", report.Names[1].Name)
+	assert.Nil(t, report.Names[1].OldestRevision)
+
+	assert.Equal(t, Counts{
+		References:       3,
+		Versions:         3,
+		RecipeRevisions:  4,
+		PackageIDs:       3,
+		PackageRevisions: 4,
+		Size:             1150,
+		OldestRevision:   &older,
+		NewestRevision:   &newer,
+	}, report.Total)
+
+	assert.Equal(t, map[string]map[string]int{
+		"os":       {"Linux": 2, "Windows": 1},
+		"compiler": {"gcc": 1, "Visual Studio": 1},
+		"arch":     {"x86_64": 1},
+	}, report.Settings)
+}
+
+func TestReportJSON(t *testing.T) {
+	collector := NewCollector("repo")
+	collector.AddReferences([]types.Reference{types.NewReference("zlib", "1.2.11", "_", "_", "rrev")})
+	b, err := json.Marshal(collector.Report())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"repository":"repo","total":{"references":1,"versions":1,"recipe_revisions":1,"package_ids":0,"package_revisions":0,"size":0},`+
+		`"names":[{"name":"zlib","references":1,"versions":1,"recipe_revisions":1,"package_ids":0,"package_revisions":0,"size":0}],`+
+		`"settings":{"arch":{},"compiler":{},"os":{}}}`, string(b))
+}
@@ -3,57 +3,122 @@ package types
 
 import (
 	"fmt"
-	"strings"
 	"regexp"
+	"strings"
 )
 
 // Constants to be used with Conan elements.
 const (
-	ValidConanChars       = `[a-zA-Z0-9_][a-zA-Z0-9_\+\.-]`  // Validates (regex) a part from a Conan reference
-	FilesystemPlaceHolder = "_"  // Filesystem representation of a null user or null channel in a Conan reference. 
+	ValidConanChars       = `[a-zA-Z0-9_][a-zA-Z0-9_\+\.-]` // Validates (regex) a part from a Conan reference
+	FilesystemPlaceHolder = "_"                             // Filesystem representation of a null user or null channel in a Conan reference.
+)
+
+var (
+	revisionPattern  = `[a-z0-9]+`
+	timestampPattern = `[0-9]+(?:\.[0-9]+)?`
+	fullPattern      = regexp.MustCompile(`^(?P<name>` + ValidConanChars + `+)\/(?P<version>` + ValidConanChars + `+)` +
+		`(?:@(?P<user>` + ValidConanChars + `+)(?:\/(?P<channel>` + ValidConanChars + `+))?)?` +
+		`(?:#(?P<revision>` + revisionPattern + `)(?:%(?P<timestamp>` + timestampPattern + `))?)?` +
+		`(?::(?P<pkgId>[a-z0-9]+)(?:#(?P<pkgRevision>` + revisionPattern + `)(?:%(?P<pkgTimestamp>` + timestampPattern + `))?)?)?$`)
 )
 
+// ParseString parses a string with a Conan reference or a Conan package using the Conan 2 syntax:
+// name/version[@user[/channel]][#rrev[%timestamp]][:pkgId[#prev[%timestamp]]]. It returns a `*Reference` or,
+// if the string contains a package ID, a `*Package`.
+func ParseString(str string) (interface{}, error) {
+	m := fullPattern.FindStringSubmatch(str)
+	if m == nil {
+		return nil, fmt.Errorf("String '%s' doesn't match a Conan reference", str)
+	}
+	groups := make(map[string]string)
+	for i, name := range fullPattern.SubexpNames() {
+		if name != "" {
+			groups[name] = m[i]
+		}
+	}
+
+	reference := Reference{Name: groups["name"], Version: groups["version"], Revision: groups["revision"], Timestamp: groups["timestamp"]}
+	if user := groups["user"]; user != "" {
+		reference.User = &user
+	}
+	if channel := groups["channel"]; channel != "" {
+		reference.Channel = &channel
+	}
+	if groups["pkgId"] == "" {
+		return &reference, nil
+	}
+	return &Package{Ref: reference, PackageId: groups["pkgId"], Revision: groups["pkgRevision"], Timestamp: groups["pkgTimestamp"]}, nil
+}
+
 // ParseStringReference parses a string and returns a Conan reference. The string can have any of these formats: name/version,
-// name/version@user/channel, name/version#revision, name/version@user/channel#revision.
+// name/version@user, name/version@user/channel, and any of them followed by #revision or #revision%timestamp. Use
+// `ParseString` to parse strings that may contain a package.
 func ParseStringReference(reference string) (*Reference, error) {
-	referencePattern := regexp.MustCompile(`^(?P<name>` + ValidConanChars + `+)\/(?P<version>` + ValidConanChars + `+)(@(?P<user>` + ValidConanChars + `+)\/(?P<channel>` + ValidConanChars + `+))?(#(?P<revision>[a-z0-9]+))?$`)
-	m := referencePattern.FindStringSubmatch(reference)
-	if m == nil {
-		return nil, fmt.Errorf("String '%s' doesn't match a Conan reference", reference)
+	item, err := ParseString(reference)
+	if err != nil {
+		return nil, err
+	}
+	ref, ok := item.(*Reference)
+	if !ok {
+		return nil, fmt.Errorf("String '%s' is a Conan package, not a reference", reference)
 	}
-	name := m[1]
-	version := m[2]
-	user := m[4]
-	channel := m[5]
-	revision := m[7]
+	return ref, nil
+}
 
-	if user == "" || channel == "" {
-		return &Reference{Name: name, Version: version, User: nil, Channel: nil, Revision: revision}, nil
+// ParseStringPackage parses a string and returns a Conan package. The string has to contain a reference (see
+// `ParseStringReference`) followed by :pkgId, :pkgId#prev or :pkgId#prev%timestamp.
+func ParseStringPackage(pkg string) (*Package, error) {
+	item, err := ParseString(pkg)
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := item.(*Package)
+	if !ok {
+		return nil, fmt.Errorf("String '%s' is a Conan reference, not a package", pkg)
 	}
-	return &Reference{Name: name, Version: version, User: &user, Channel: &channel, Revision: revision}, nil
+	return ret, nil
 }
 
 // Reference represents a Conan reference with its parts: name, version, user, channel and revision. Only the attributes
-// `Channel` and `User` are optional in a valid reference.
+// `Channel` and `User` are optional in a valid reference. The `Timestamp` of the revision (seconds since epoch, as written
+// in Conan 2 references) is optional too.
 type Reference struct {
-	Name     string
-	Version  string
-	User     *string
-	Channel  *string
-	Revision string
+	Name      string
+	Version   string
+	User      *string
+	Channel   *string
+	Revision  string
+	Timestamp string
+}
+
+// NewReference creates a `Reference` from the parts found in an Artifactory path: empty values and the
+// `FilesystemPlaceHolder` are considered null values for `user` and `channel`.
+func NewReference(name string, version string, user string, channel string, revision string) Reference {
+	reference := Reference{Name: name, Version: version, Revision: revision}
+	if user != "" && user != FilesystemPlaceHolder {
+		reference.User = &user
+		if channel != "" && channel != FilesystemPlaceHolder {
+			reference.Channel = &channel
+		}
+	}
+	return reference
 }
 
 // ToString returns a string representation of the `Reference`. Use the argument `withRevision` to add or not the
-// revision to the output.
+// revision (and its timestamp) to the output.
 func (ref *Reference) ToString(withRevision bool) string {
-	var ret string
+	ret := fmt.Sprintf("%s/%s", ref.Name, ref.Version)
 	if ref.User != nil {
-		ret = fmt.Sprintf("%s/%s@%s/%s", ref.Name, ref.Version, *ref.User, *ref.Channel)
-	} else {
-		ret = fmt.Sprintf("%s/%s", ref.Name, ref.Version)
+		ret = ret + "@" + *ref.User
+		if ref.Channel != nil {
+			ret = ret + "/" + *ref.Channel
+		}
 	}
-	if withRevision {
+	if withRevision && ref.Revision != "" {
 		ret = ret + "#" + ref.Revision
+		if ref.Timestamp != "" {
+			ret = ret + "%" + ref.Timestamp
+		}
 	}
 	return ret
 }
@@ -64,7 +129,7 @@ func (ref *Reference) String() string {
 }
 
 // RtPath returns the path inside Artifactory to the `Reference`. It can be considered with
-// or without revisions (latest element in the Artifactory path). 
+// or without revisions (latest element in the Artifactory path).
 func (ref *Reference) RtPath(withRevision bool) string {
 	var user string
 	if ref.User == nil {
@@ -87,11 +152,13 @@ func (ref *Reference) RtPath(withRevision bool) string {
 	return strings.Join(str, "/")
 }
 
-// Package represents a Conan package with its `Reference`, the package ID and the package revision.
+// Package represents a Conan package with its `Reference`, the package ID and the package revision (and, optionally,
+// the `Timestamp` of the package revision).
 type Package struct {
 	Ref       Reference
 	PackageId string
 	Revision  string
+	Timestamp string
 }
 
 func (pkg *Package) String() string {
@@ -102,15 +169,18 @@ func (pkg *Package) String() string {
 // revisions to the output.
 func (pkg *Package) ToString(withRevision bool) string {
 	ret := pkg.Ref.ToString(withRevision) + ":" + pkg.PackageId
-	if withRevision {
+	if withRevision && pkg.Revision != "" {
 		ret = ret + "#" + pkg.Revision
+		if pkg.Timestamp != "" {
+			ret = ret + "%" + pkg.Timestamp
+		}
 	}
 	return ret
 }
 
 // RtPath returns the path inside Artifactory to the `Package`. It can be considered with
 // or without revisions (last element in the Artifactory path). However, not that the recipe
-// will always contain the revision, as it is an element in the middle of the path. 
+// will always contain the revision, as it is an element in the middle of the path.
 func (pkg *Package) RtPath(withRevision bool) string {
 	str := []string{pkg.Ref.RtPath(true), "package", pkg.PackageId}
 	if withRevision {
@@ -7,7 +7,7 @@ import (
 )
 
 func TestReference(t *testing.T) {
-	reference := Reference{"name", "version", nil, nil, "rrev"}
+	reference := Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
 	assert.Equal(t, reference.Name, "name")
 	assert.Equal(t, reference.Version, "version")
 	assert.Nil(t, reference.User)
@@ -23,7 +23,7 @@ func TestReference(t *testing.T) {
 func TestReferenceUserChannel(t *testing.T) {
 	user := "user"
 	channel := "channel"
-	reference := Reference{"name", "version", &user, &channel, "rrev"}
+	reference := Reference{Name: "name", Version: "version", User: &user, Channel: &channel, Revision: "rrev"}
 	assert.Equal(t, reference.Name, "name")
 	assert.Equal(t, reference.Version, "version")
 	assert.Equal(t, *reference.User, "user")
@@ -38,7 +38,7 @@ func TestReferenceUserChannel(t *testing.T) {
 }
 
 func TestPackage(t *testing.T) {
-	reference := Reference{"name", "version", nil, nil, "rrev"}
+	reference := Reference{Name: "name", Version: "version", User: nil, Channel: nil, Revision: "rrev"}
 	conanPackage := Package{Ref: reference, PackageId: "pkgId", Revision: "prev"}
 
 	assert.Equal(t, conanPackage.ToString(true), "name/version#rrev:pkgId#prev")
@@ -100,3 +100,121 @@ func TestParseStringReferenceErrors(t *testing.T) {
 	assert.Equal(t, "String 'name/version@u/c' doesn't match a Conan reference", err.Error())
 
 }
+
+func TestParseString(t *testing.T) {
+	testCases := []struct {
+		input        string
+		isPackage    bool
+		name         string
+		version      string
+		user         string
+		channel      string
+		revision     string
+		timestamp    string
+		pkgID        string
+		pkgRevision  string
+		pkgTimestamp string
+	}{
+		{input: "name/version", name: "name", version: "version"},
+		{input: "name/version@user", name: "name", version: "version", user: "user"},
+		{input: "name/version@user/channel", name: "name", version: "version", user: "user", channel: "channel"},
+		{input: "name/version#rrev", name: "name", version: "version", revision: "rrev"},
+		{input: "name/version#rrev%1612345678", name: "name", version: "version", revision: "rrev", timestamp: "1612345678"},
+		{input: "name/version#rrev%1612345678.123", name: "name", version: "version", revision: "rrev", timestamp: "1612345678.123"},
+		{input: "name/version@user#rrev", name: "name", version: "version", user: "user", revision: "rrev"},
+		{input: "name/version@user/channel#rrev%1612345678.123", name: "name", version: "version", user: "user", channel: "channel", revision: "rrev", timestamp: "1612345678.123"},
+		{input: "name/version:pkgid", isPackage: true, name: "name", version: "version", pkgID: "pkgid"},
+		{input: "name/version#rrev:pkgid", isPackage: true, name: "name", version: "version", revision: "rrev", pkgID: "pkgid"},
+		{input: "name/version#rrev:pkgid#prev", isPackage: true, name: "name", version: "version", revision: "rrev", pkgID: "pkgid", pkgRevision: "prev"},
+		{input: "name/version@user#rrev:pkgid#prev%1612345679", isPackage: true, name: "name", version: "version", user: "user", revision: "rrev", pkgID: "pkgid", pkgRevision: "prev", pkgTimestamp: "1612345679"},
+		{input: "name/version@user/channel#rrev%1612345678.1:pkgid#prev%1612345679.2", isPackage: true, name: "name", version: "version", user: "user", channel: "channel", revision: "rrev", timestamp: "1612345678.1", pkgID: "pkgid", pkgRevision: "prev", pkgTimestamp: "1612345679.2"},
+	}
+
+	for _, tc := range testCases {
+		item, err := ParseString(tc.input)
+		assert.Nil(t, err, tc.input)
+
+		var ref *Reference
+		if tc.isPackage {
+			pkg, ok := item.(*Package)
+			assert.True(t, ok, tc.input)
+			assert.Equal(t, tc.pkgID, pkg.PackageId, tc.input)
+			assert.Equal(t, tc.pkgRevision, pkg.Revision, tc.input)
+			assert.Equal(t, tc.pkgTimestamp, pkg.Timestamp, tc.input)
+			assert.Equal(t, tc.input, pkg.ToString(true), tc.input)
+			ref = &pkg.Ref
+
+			_, err = ParseStringReference(tc.input)
+			assert.NotNil(t, err, tc.input)
+			assert.Equal(t, "String '"+tc.input+"' is a Conan package, not a reference", err.Error())
+		} else {
+			var ok bool
+			ref, ok = item.(*Reference)
+			assert.True(t, ok, tc.input)
+			assert.Equal(t, tc.input, ref.ToString(true), tc.input)
+
+			_, err = ParseStringPackage(tc.input)
+			assert.NotNil(t, err, tc.input)
+			assert.Equal(t, "String '"+tc.input+"' is a Conan reference, not a package", err.Error())
+		}
+
+		assert.Equal(t, tc.name, ref.Name, tc.input)
+		assert.Equal(t, tc.version, ref.Version, tc.input)
+		if tc.user == "" {
+			assert.Nil(t, ref.User, tc.input)
+		} else {
+			assert.Equal(t, tc.user, *ref.User, tc.input)
+		}
+		if tc.channel == "" {
+			assert.Nil(t, ref.Channel, tc.input)
+		} else {
+			assert.Equal(t, tc.channel, *ref.Channel, tc.input)
+		}
+		assert.Equal(t, tc.revision, ref.Revision, tc.input)
+		assert.Equal(t, tc.timestamp, ref.Timestamp, tc.input)
+	}
+}
+
+func TestParseStringErrors(t *testing.T) {
+	testCases := []string{
+		"name",
+		"name/version@",
+		"name/version@user/",
+		"name/version#",
+		"name/version#rrev%",
+		"name/version#rrev%abc",
+		"name/version:",
+		"name/version:pkgid#",
+		"name/version:pkgid#prev%",
+		"name/version#rrev:pkgid#prev:other",
+		"name/version@/channel",
+	}
+	for _, input := range testCases {
+		_, err := ParseString(input)
+		assert.NotNil(t, err, input)
+		assert.Equal(t, "String '"+input+"' doesn't match a Conan reference", err.Error())
+	}
+}
+
+func TestReferenceUserOnly(t *testing.T) {
+	user := "user"
+	reference := Reference{Name: "name", Version: "version", User: &user, Channel: nil, Revision: "rrev", Timestamp: "1612345678"}
+	assert.Equal(t, "name/version@user#rrev%1612345678", reference.ToString(true))
+	assert.Equal(t, "name/version@user", reference.ToString(false))
+	assert.Equal(t, "user/name/version/_/rrev", reference.RtPath(true))
+	assert.Equal(t, "user/name/version/_", reference.RtPath(false))
+}
+
+func TestNewReference(t *testing.T) {
+	reference := NewReference("name", "version", "_", "_", "rrev")
+	assert.Equal(t, "name/version#rrev", reference.ToString(true))
+	assert.Equal(t, "_/name/version/_/rrev", reference.RtPath(true))
+
+	reference = NewReference("name", "version", "user", "_", "rrev")
+	assert.Equal(t, "name/version@user#rrev", reference.ToString(true))
+	assert.Nil(t, reference.Channel)
+
+	reference = NewReference("name", "version", "user", "channel", "rrev")
+	assert.Equal(t, "name/version@user/channel#rrev", reference.ToString(true))
+	assert.Equal(t, "user/name/version/channel/rrev", reference.RtPath(true))
+}
@@ -2,15 +2,17 @@
 package types
 
 import (
+	"sort"
 	"strings"
 	"time"
 )
 
 const (
-	timeLayout = "2006-01-02T15:04:05.999+0000"
+	timeLayout       = "2006-01-02T15:04:05.999+0000"
+	timeOutputLayout = "2006-01-02T15:04:05.000+0000"
 )
 
-// RtTimestamp represents a custom timestamp using format '2006-01-02T15:04:05.999+0000'. It allows 
+// RtTimestamp represents a custom timestamp using format '2006-01-02T15:04:05.999+0000'. It allows
 // serializing and deserializing using the representation used by Artifactory.
 type RtTimestamp struct {
 	time.Time
@@ -28,10 +30,15 @@ func (ct *RtTimestamp) UnmarshalJSON(b []byte) (err error) {
 	return
 }
 
+// MarshalJSON serializes the timestamp using the representation used by Artifactory.
+func (ct RtTimestamp) MarshalJSON() ([]byte, error) {
+	return []byte("\"" + ct.Time.UTC().Format(timeOutputLayout) + "\""), nil
+}
+
 // RtRevisionsData represents the data associated to a Conan revision in Artifactory.
 type RtRevisionsData struct {
-	Revision string
-	Time     RtTimestamp
+	Revision string      `json:"revision"`
+	Time     RtTimestamp `json:"time"`
 }
 
 // ByTime is a helper operator to order revisions by date.
@@ -41,8 +48,46 @@ func (a ByTime) Len() int           { return len(a) }
 func (a ByTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByTime) Less(i, j int) bool { return a[i].Time.Before(a[j].Time.Time) }
 
-// RtIndexJSON represents the JSON where Artifactory stores Conan revisions (using 'index.json' files).
+// RtIndexJSON represents the JSON where Artifactory stores Conan revisions (using 'index.json' files). Files for
+// recipe revisions contain the `Reference` and files for package revisions the `PackageReference`.
 type RtIndexJSON struct {
-	//Reference string
-	Revisions []RtRevisionsData
+	Reference        string            `json:"reference,omitempty"`
+	PackageReference string            `json:"packageReference,omitempty"`
+	Revisions        []RtRevisionsData `json:"revisions"`
+}
+
+// RemoveRevisions removes from the index the given `revisions` (only the revision is considered).
+func (index *RtIndexJSON) RemoveRevisions(revisions []RtRevisionsData) {
+	remaining := []RtRevisionsData{}
+	for _, revision := range index.Revisions {
+		found := false
+		for _, r := range revisions {
+			if r.Revision == revision.Revision {
+				found = true
+				break
+			}
+		}
+		if !found {
+			remaining = append(remaining, revision)
+		}
+	}
+	index.Revisions = remaining
+}
+
+// AddRevisions adds to the index the given `revisions` that are not already listed (only the revision is considered),
+// keeping them sorted from the newest to the oldest one like Artifactory does.
+func (index *RtIndexJSON) AddRevisions(revisions []RtRevisionsData) {
+	for _, revision := range revisions {
+		found := false
+		for _, r := range index.Revisions {
+			if r.Revision == revision.Revision {
+				found = true
+				break
+			}
+		}
+		if !found {
+			index.Revisions = append(index.Revisions, revision)
+		}
+	}
+	sort.Sort(sort.Reverse(ByTime(index.Revisions)))
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -42,3 +43,29 @@ func TestOrderByTime(t *testing.T) {
 	assert.Equal(t, revisions.Revisions[1].Revision, "3c07b6a54477e856d429493d01c85636")
 
 }
+
+func TestWriteJSON(t *testing.T) {
+	var revisions RtIndexJSON
+	err := json.Unmarshal([]byte(content), &revisions)
+	assert.Nil(t, err)
+	assert.Equal(t, "b2/4.0.0@_/_", revisions.Reference)
+
+	revisions.RemoveRevisions([]RtRevisionsData{{Revision: "3c07b6a54477e856d429493d01c85636"}})
+	b, err := json.Marshal(revisions)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"reference":"b2/4.0.0@_/_","revisions":[{"revision":"5918010f58ef4294511ff176ccc236b0","time":"2020-08-17T15:20:47.871+0000"}]}`, string(b))
+}
+
+func TestAddRevisions(t *testing.T) {
+	var revisions RtIndexJSON
+	err := json.Unmarshal([]byte(content), &revisions)
+	assert.Nil(t, err)
+
+	newer := RtRevisionsData{Revision: "newer", Time: RtTimestamp{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	revisions.AddRevisions([]RtRevisionsData{newer, {Revision: "5918010f58ef4294511ff176ccc236b0"}})
+	assert.Equal(t, 3, len(revisions.Revisions))
+	assert.Equal(t, "newer", revisions.Revisions[0].Revision)
+	assert.Equal(t, "3c07b6a54477e856d429493d01c85636", revisions.Revisions[1].Revision)
+	assert.Equal(t, "5918010f58ef4294511ff176ccc236b0", revisions.Revisions[2].Revision)
+	assert.False(t, revisions.Revisions[2].Time.IsZero())
+}
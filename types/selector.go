@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const selectorDateLayout = "2006-01-02"
+
+var selectorPattern = regexp.MustCompile(`^(?P<reference>.+?)(?:#(?P<latest>latest)(?:~(?P<offset>[0-9]+))?|#(?P<all>all)|@(?P<date>[0-9]{4}-[0-9]{2}-[0-9]{2}))$`)
+
+// RevisionSelector selects recipe revisions from the list of revisions of a reference: the `Latest` one (or the one
+// `Offset` positions before it), the one current at a `Date` or `All` of them.
+type RevisionSelector struct {
+	Latest bool
+	Offset int
+	Date   time.Time
+	All    bool
+}
+
+// String returns the selector using the syntax accepted by `ParseReferenceSelector`.
+func (selector *RevisionSelector) String() string {
+	switch {
+	case selector.All:
+		return "#all"
+	case !selector.Date.IsZero():
+		return "@" + selector.Date.Format(selectorDateLayout)
+	case selector.Offset > 0:
+		return "#latest~" + strconv.Itoa(selector.Offset)
+	}
+	return "#latest"
+}
+
+// ParseReferenceSelector parses a Conan reference (see `ParseStringReference`) optionally followed by a revision
+// selector: '#latest', '#latest~N' (N-th revision before the latest one), '@YYYY-MM-DD' (latest revision at the end
+// of that day, UTC) or '#all'. If the reference has no revision nor selector, it selects the latest revision; if it
+// has an explicit revision, the returned selector is nil.
+func ParseReferenceSelector(str string) (*Reference, *RevisionSelector, error) {
+	m := selectorPattern.FindStringSubmatch(str)
+	if m == nil {
+		ref, err := ParseStringReference(str)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ref.Revision != "" {
+			return ref, nil, nil
+		}
+		return ref, &RevisionSelector{Latest: true}, nil
+	}
+	groups := make(map[string]string)
+	for i, name := range selectorPattern.SubexpNames() {
+		if name != "" {
+			groups[name] = m[i]
+		}
+	}
+
+	ref, err := ParseStringReference(groups["reference"])
+	if err != nil {
+		return nil, nil, err
+	}
+	if ref.Revision != "" {
+		return nil, nil, fmt.Errorf("String '%s' contains both a revision and a revision selector", str)
+	}
+	selector := &RevisionSelector{}
+	switch {
+	case groups["all"] != "":
+		selector.All = true
+	case groups["date"] != "":
+		selector.Date, err = time.Parse(selectorDateLayout, groups["date"])
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid date in '%s': %s", str, err)
+		}
+	default:
+		selector.Latest = true
+		if groups["offset"] != "" {
+			selector.Offset, _ = strconv.Atoi(groups["offset"])
+		}
+	}
+	return ref, selector, nil
+}
+
+// Select returns the selected revisions from `revisions` (sorted by time, see `ByTime`).
+func (selector *RevisionSelector) Select(revisions []RtRevisionsData) ([]RtRevisionsData, error) {
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("No revisions to select '%s' from", selector)
+	}
+	switch {
+	case selector.All:
+		return revisions, nil
+	case !selector.Date.IsZero():
+		end := selector.Date.AddDate(0, 0, 1)
+		for i := len(revisions) - 1; i >= 0; i-- {
+			if revisions[i].Time.Before(end) {
+				return revisions[i : i+1], nil
+			}
+		}
+		return nil, fmt.Errorf("No revision found at %s, the first one is from %s", selector.Date.Format(selectorDateLayout), revisions[0].Time.Format(selectorDateLayout))
+	}
+	i := len(revisions) - 1 - selector.Offset
+	if i < 0 {
+		return nil, fmt.Errorf("Cannot select '%s', there are only %d revisions", selector, len(revisions))
+	}
+	return revisions[i : i+1], nil
+}
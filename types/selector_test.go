@@ -0,0 +1,82 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReferenceSelector(t *testing.T) {
+	testCases := []struct {
+		input     string
+		reference string
+		selector  string
+	}{
+		{"name/version", "name/version", "#latest"},
+		{"name/version#latest", "name/version", "#latest"},
+		{"name/version@user/channel#latest~2", "name/version@user/channel", "#latest~2"},
+		{"name/version@user#all", "name/version@user", "#all"},
+		{"name/version@2021-03-01", "name/version", "@2021-03-01"},
+		{"name/version@user/channel@2021-03-01", "name/version@user/channel", "@2021-03-01"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			ref, selector, err := ParseReferenceSelector(tc.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.reference, ref.ToString(true))
+			assert.Equal(t, tc.selector, selector.String())
+		})
+	}
+
+	ref, selector, err := ParseReferenceSelector("name/version#rrev")
+	assert.Nil(t, err)
+	assert.Equal(t, "name/version#rrev", ref.ToString(true))
+	assert.Nil(t, selector)
+
+	_, _, err = ParseReferenceSelector("name/version#rrev#latest")
+	assert.Equal(t, "String 'name/version#rrev#latest' contains both a revision and a revision selector", err.Error())
+	_, _, err = ParseReferenceSelector("name/version@2021-13-01")
+	assert.Equal(t, "Invalid date in 'name/version@2021-13-01': parsing time \"2021-13-01\": month out of range", err.Error())
+	_, _, err = ParseReferenceSelector("name/version#latest~")
+	assert.NotNil(t, err)
+}
+
+func TestRevisionSelectorSelect(t *testing.T) {
+	day := func(d int) RtTimestamp { return RtTimestamp{Time: time.Date(2021, 3, d, 12, 0, 0, 0, time.UTC)} }
+	revisions := []RtRevisionsData{{"rrev1", day(1)}, {"rrev2", day(3)}, {"rrev3", day(5)}}
+	selected := func(str string) (string, error) {
+		_, selector, err := ParseReferenceSelector("name/version" + str)
+		assert.Nil(t, err)
+		ret, err := selector.Select(revisions)
+		if err != nil {
+			return "", err
+		}
+		names := ""
+		for _, revision := range ret {
+			names += revision.Revision + " "
+		}
+		return names, nil
+	}
+
+	for input, expected := range map[string]string{
+		"#latest":     "rrev3 ",
+		"#latest~0":   "rrev3 ",
+		"#latest~2":   "rrev1 ",
+		"@2021-03-01": "rrev1 ",
+		"@2021-03-04": "rrev2 ",
+		"@2022-01-01": "rrev3 ",
+		"#all":        "rrev1 rrev2 rrev3 ",
+	} {
+		names, err := selected(input)
+		assert.Nil(t, err, input)
+		assert.Equal(t, expected, names, input)
+	}
+
+	_, err := selected("#latest~3")
+	assert.Equal(t, "Cannot select '#latest~3', there are only 3 revisions", err.Error())
+	_, err = selected("@2021-02-28")
+	assert.Equal(t, "No revision found at 2021-02-28, the first one is from 2021-03-01", err.Error())
+	_, err = (&RevisionSelector{Latest: true}).Select(nil)
+	assert.Equal(t, "No revisions to select '#latest' from", err.Error())
+}
@@ -0,0 +1,277 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version represents a Conan version. Versions are split into main items (separated by dots), an optional
+// prerelease (after the first '-') and an optional build (after the first '+'). It can represent any string,
+// not only semver ones, for example versions like 'cci.20210101'.
+type Version struct {
+	str        string
+	main       []string
+	prerelease string
+	build      string
+}
+
+// ParseVersion returns the `Version` for the given string.
+func ParseVersion(str string) Version {
+	v := Version{str: str}
+	rest := str
+	if i := strings.Index(rest, "+"); i >= 0 {
+		v.build = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.Index(rest, "-"); i >= 0 {
+		v.prerelease = rest[i+1:]
+		rest = rest[:i]
+	}
+	v.main = strings.Split(rest, ".")
+	return v
+}
+
+// String returns the version as it was given.
+func (v Version) String() string {
+	return v.str
+}
+
+// IsPrerelease returns true if the version has a prerelease part.
+func (v Version) IsPrerelease() bool {
+	return v.prerelease != ""
+}
+
+// Compare returns an integer comparing two versions: 0 if `v` == `other`, -1 if `v` < `other` and +1 if
+// `v` > `other`. Main items are compared one by one, numerically if both are numbers, otherwise numbers are
+// lower than strings and strings are compared lexicographically; missing items are considered zero. A version
+// with a prerelease is lower than the same version without it. Build metadata is only used to break ties.
+func (v Version) Compare(other Version) int {
+	if c := compareItems(v.main, other.main); c != 0 {
+		return c
+	}
+	if v.prerelease != other.prerelease {
+		if v.prerelease == "" {
+			return 1
+		}
+		if other.prerelease == "" {
+			return -1
+		}
+		if c := compareItems(strings.Split(v.prerelease, "."), strings.Split(other.prerelease, ".")); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(v.build, other.build)
+}
+
+func compareItems(lhs []string, rhs []string) int {
+	for i := 0; i < len(lhs) || i < len(rhs); i++ {
+		l, r := "0", "0"
+		if i < len(lhs) {
+			l = lhs[i]
+		}
+		if i < len(rhs) {
+			r = rhs[i]
+		}
+		if c := compareItem(l, r); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareItem(lhs string, rhs string) int {
+	lInt, lErr := strconv.ParseUint(lhs, 10, 64)
+	rInt, rErr := strconv.ParseUint(rhs, 10, 64)
+	switch {
+	case lErr == nil && rErr == nil:
+		if lInt < rInt {
+			return -1
+		} else if lInt > rInt {
+			return 1
+		}
+		return 0
+	case lErr == nil:
+		return -1
+	case rErr == nil:
+		return 1
+	}
+	return strings.Compare(lhs, rhs)
+}
+
+// upperBound returns the version resulting of incrementing the main item at `index` and dropping the following ones.
+func (v Version) upperBound(index int) Version {
+	items := append([]string{}, v.main[:index+1]...)
+	if n, err := strconv.ParseUint(items[index], 10, 64); err == nil {
+		items[index] = strconv.FormatUint(n+1, 10)
+	} else {
+		items[index] = items[index] + "~"
+	}
+	return ParseVersion(strings.Join(items, "."))
+}
+
+type versionCondition struct {
+	operator string
+	version  Version
+}
+
+func (c versionCondition) matches(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.operator {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "*":
+		return true
+	}
+	return cmp == 0
+}
+
+// VersionRange represents a Conan version range expression like '[>=1.2 <2]', '[~1.2]', '[^1.2]' or
+// '[>1 <2 || 3.0]'. Conditions separated by spaces or commas must all be satisfied, and any of the sets
+// separated by '||' has to match. Prereleases only match if the expression contains a prerelease or the
+// 'include_prerelease' option.
+type VersionRange struct {
+	expression        string
+	conditionSets     [][]versionCondition
+	includePrerelease bool
+}
+
+// ParseVersionRange parses the given expression (with or without the surrounding brackets) and returns a `VersionRange`.
+func ParseVersionRange(expression string) (*VersionRange, error) {
+	str := strings.TrimSpace(expression)
+	if strings.HasPrefix(str, "[") {
+		if !strings.HasSuffix(str, "]") {
+			return nil, fmt.Errorf("Invalid version range '%s'", expression)
+		}
+		str = str[1 : len(str)-1]
+	}
+
+	versionRange := &VersionRange{expression: expression}
+	for _, set := range strings.Split(str, "||") {
+		conditions := []versionCondition{}
+		for _, token := range strings.FieldsFunc(set, func(r rune) bool { return r == ' ' || r == ',' }) {
+			if token == "include_prerelease" || token == "include_prerelease=True" {
+				versionRange.includePrerelease = true
+				continue
+			}
+			parsed, err := parseVersionCondition(token)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid version range '%s': %s", expression, err)
+			}
+			for _, condition := range parsed {
+				if condition.version.IsPrerelease() {
+					versionRange.includePrerelease = true
+				}
+			}
+			conditions = append(conditions, parsed...)
+		}
+		if len(conditions) == 0 {
+			return nil, fmt.Errorf("Invalid version range '%s': empty condition", expression)
+		}
+		versionRange.conditionSets = append(versionRange.conditionSets, conditions)
+	}
+	return versionRange, nil
+}
+
+func parseVersionCondition(token string) ([]versionCondition, error) {
+	if token == "*" {
+		return []versionCondition{{operator: "*"}}, nil
+	}
+	for _, operator := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if !strings.HasPrefix(token, operator) {
+			continue
+		}
+		value := token[len(operator):]
+		if value == "" {
+			return nil, fmt.Errorf("missing version after '%s'", operator)
+		}
+		v := ParseVersion(value)
+		switch operator {
+		case "~":
+			index := 0
+			if len(v.main) > 1 {
+				index = 1
+			}
+			return []versionCondition{{">=", v}, {"<", v.upperBound(index)}}, nil
+		case "^":
+			index := len(v.main) - 1
+			for i, item := range v.main {
+				if item != "0" {
+					index = i
+					break
+				}
+			}
+			return []versionCondition{{">=", v}, {"<", v.upperBound(index)}}, nil
+		}
+		return []versionCondition{{operator, v}}, nil
+	}
+	return []versionCondition{{"=", ParseVersion(token)}}, nil
+}
+
+// String returns the expression used to create the `VersionRange`.
+func (r *VersionRange) String() string {
+	return r.expression
+}
+
+// Contains returns true if the version `v` satisfies the range.
+func (r *VersionRange) Contains(v Version) bool {
+	if v.IsPrerelease() && !r.includePrerelease {
+		return false
+	}
+	for _, conditions := range r.conditionSets {
+		matches := true
+		for _, condition := range conditions {
+			if !condition.matches(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// ByVersion is a helper operator to order references by name, version (using Conan ordering), user, channel and revision.
+type ByVersion []Reference
+
+func (a ByVersion) Len() int      { return len(a) }
+func (a ByVersion) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByVersion) Less(i, j int) bool {
+	return compareReferences(a[i], a[j]) < 0
+}
+
+// PackagesByVersion is a helper operator to order packages by reference (see `ByVersion`), package ID and package revision.
+type PackagesByVersion []Package
+
+func (a PackagesByVersion) Len() int      { return len(a) }
+func (a PackagesByVersion) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a PackagesByVersion) Less(i, j int) bool {
+	if c := compareReferences(a[i].Ref, a[j].Ref); c != 0 {
+		return c < 0
+	}
+	if a[i].PackageId != a[j].PackageId {
+		return a[i].PackageId < a[j].PackageId
+	}
+	return a[i].Revision < a[j].Revision
+}
+
+func compareReferences(lhs Reference, rhs Reference) int {
+	if lhs.Name != rhs.Name {
+		return strings.Compare(lhs.Name, rhs.Name)
+	}
+	if c := ParseVersion(lhs.Version).Compare(ParseVersion(rhs.Version)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(lhs.ToString(false), rhs.ToString(false)); c != 0 {
+		return c
+	}
+	return strings.Compare(lhs.Revision, rhs.Revision)
+}
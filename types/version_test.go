@@ -0,0 +1,124 @@
+package types
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCompare(t *testing.T) {
+	testCases := []struct {
+		lhs      string
+		rhs      string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"4.3.0", "4.2.0", 1},
+		{"1.0-pre", "1.0", -1},
+		{"1.0-alpha", "1.0-beta", -1},
+		{"1.0-rc.2", "1.0-rc.10", -1},
+		{"1.0+build1", "1.0+build2", -1},
+		{"1.1-pre", "1.0", 1},
+		{"1.1.1c", "1.1.1f", -1},
+		{"1.1.1", "1.1.1c", -1},
+		{"cci.20200101", "cci.20210301", -1},
+		{"1.0", "cci.20200101", -1},
+		{"system", "1.0", 1},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, ParseVersion(tc.lhs).Compare(ParseVersion(tc.rhs)), tc.lhs+" vs "+tc.rhs)
+		assert.Equal(t, -tc.expected, ParseVersion(tc.rhs).Compare(ParseVersion(tc.lhs)), tc.rhs+" vs "+tc.lhs)
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	testCases := []struct {
+		expression string
+		matches    []string
+		nomatches  []string
+	}{
+		{"[>=1.2 <2]", []string{"1.2", "1.2.0", "1.10", "1.99.9"}, []string{"1.1", "2", "2.0", "1.5-pre"}},
+		{">1.2,<=2", []string{"1.3", "2.0"}, []string{"1.2", "2.1"}},
+		{"[~1.2]", []string{"1.2", "1.2.9"}, []string{"1.3", "1.1"}},
+		{"[~1]", []string{"1.0", "1.9"}, []string{"2.0"}},
+		{"[^1.2]", []string{"1.2", "1.9"}, []string{"2.0", "1.1"}},
+		{"[^0.2.1]", []string{"0.2.1", "0.2.9"}, []string{"0.3", "0.2.0"}},
+		{"[1.2]", []string{"1.2", "1.2.0"}, []string{"1.2.1"}},
+		{"[=1.2]", []string{"1.2"}, []string{"1.3"}},
+		{"[*]", []string{"1.2", "cci.20200101"}, []string{"1.0-pre"}},
+		{"[<1 || >=2 <3]", []string{"0.5", "2.5"}, []string{"1.5", "3.0"}},
+		{"[>=1.0-pre <2]", []string{"1.0-pre", "1.5-pre", "1.5"}, []string{"0.9"}},
+		{"[>=1.0 <2, include_prerelease]", []string{"1.5-pre", "2.0-pre"}, []string{"2.0", "1.0-pre"}},
+		{"[>=cci.20200101]", []string{"cci.20210101"}, []string{"cci.20191231", "1.0"}},
+	}
+	for _, tc := range testCases {
+		versionRange, err := ParseVersionRange(tc.expression)
+		assert.Nil(t, err, tc.expression)
+		assert.Equal(t, tc.expression, versionRange.String())
+		for _, v := range tc.matches {
+			assert.True(t, versionRange.Contains(ParseVersion(v)), tc.expression+" should contain "+v)
+		}
+		for _, v := range tc.nomatches {
+			assert.False(t, versionRange.Contains(ParseVersion(v)), tc.expression+" should not contain "+v)
+		}
+	}
+}
+
+func TestVersionRangeErrors(t *testing.T) {
+	_, err := ParseVersionRange("[>=1.2")
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid version range '[>=1.2'", err.Error())
+
+	_, err = ParseVersionRange("[>= <2]")
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid version range '[>= <2]': missing version after '>='", err.Error())
+
+	_, err = ParseVersionRange("[]")
+	assert.NotNil(t, err)
+	assert.Equal(t, "Invalid version range '[]': empty condition", err.Error())
+}
+
+func TestSortByVersion(t *testing.T) {
+	references := []Reference{
+		NewReference("b2", "4.0.0", "_", "_", "rrev"),
+		NewReference("b2", "4.0.1", "_", "_", "rrev"),
+		NewReference("b2", "4.2.0", "_", "_", "rrev"),
+		NewReference("b2", "4.1.0", "_", "_", "rrev"),
+		NewReference("b2", "4.10.0", "_", "_", "rrev"),
+		NewReference("b2", "4.3.0", "_", "_", "rrev2"),
+		NewReference("b2", "4.3.0", "_", "_", "rrev1"),
+		NewReference("boost", "1.73.0", "_", "_", "rrev"),
+		NewReference("abseil", "20200225.2", "_", "_", "rrev"),
+	}
+	sort.Sort(ByVersion(references))
+	result := []string{}
+	for _, ref := range references {
+		result = append(result, ref.ToString(true))
+	}
+	assert.Equal(t, []string{
+		"abseil/20200225.2#rrev",
+		"b2/4.0.0#rrev",
+		"b2/4.0.1#rrev",
+		"b2/4.1.0#rrev",
+		"b2/4.2.0#rrev",
+		"b2/4.3.0#rrev1",
+		"b2/4.3.0#rrev2",
+		"b2/4.10.0#rrev",
+		"boost/1.73.0#rrev",
+	}, result)
+
+	packages := []Package{
+		{Ref: references[2], PackageId: "b", Revision: "prev"},
+		{Ref: references[1], PackageId: "b", Revision: "prev"},
+		{Ref: references[2], PackageId: "a", Revision: "prev2"},
+		{Ref: references[2], PackageId: "a", Revision: "prev1"},
+	}
+	sort.Sort(PackagesByVersion(packages))
+	assert.Equal(t, "b2/4.0.0#rrev:b#prev", packages[0].String())
+	assert.Equal(t, "b2/4.0.1#rrev:a#prev1", packages[1].String())
+	assert.Equal(t, "b2/4.0.1#rrev:a#prev2", packages[2].String())
+	assert.Equal(t, "b2/4.0.1#rrev:b#prev", packages[3].String())
+}
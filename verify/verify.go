@@ -0,0 +1,223 @@
+// Package verify contains the functionality to check the consistency between the folders of a Conan repository
+// in Artifactory and the revisions listed in its 'index.json' files.
+package verify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+)
+
+// Kind is the type of inconsistency found.
+type Kind string
+
+const (
+	// MissingIndex is used for folders with revisions and without 'index.json'.
+	MissingIndex Kind = "missing-index"
+	// MissingFolder is used for revisions listed in the 'index.json' whose folder doesn't exist.
+	MissingFolder Kind = "missing-folder"
+	// UnlistedFolder is used for revision folders that are not listed in the 'index.json'.
+	UnlistedFolder Kind = "unlisted-folder"
+	// MissingManifest is used for recipe and package revisions without 'conanmanifest.txt'.
+	MissingManifest Kind = "missing-manifest"
+	// MissingConanInfo is used for package revisions without 'conaninfo.txt'.
+	MissingConanInfo Kind = "missing-conaninfo"
+)
+
+// Fixable returns true if the issue can be fixed rewriting the 'index.json' file.
+func (kind Kind) Fixable() bool {
+	return kind == MissingIndex || kind == MissingFolder || kind == UnlistedFolder
+}
+
+// Issue is an inconsistency found in the folder `Path` (relative to the repository) that contains the revisions.
+type Issue struct {
+	Kind     Kind   `json:"kind"`
+	Path     string `json:"path"`
+	Revision string `json:"revision,omitempty"`
+}
+
+func (issue Issue) String() string {
+	if issue.Revision == "" {
+		return fmt.Sprintf("%s: %s", issue.Kind, issue.Path)
+	}
+	return fmt.Sprintf("%s: %s/%s", issue.Kind, issue.Path, issue.Revision)
+}
+
+type revision struct {
+	modified time.Time
+	files    map[string]bool
+}
+
+// folder contains the revisions of a recipe (user/name/version/channel) or of a package ID (with the recipe revision
+// and the package ID in the path).
+type folder struct {
+	reference string
+	hasIndex  bool
+	revisions map[string]*revision
+}
+
+// Tree is the layout of the repository built from the paths of its files.
+type Tree struct {
+	folders map[string]*folder
+}
+
+// NewTree returns an empty `Tree`.
+func NewTree() *Tree {
+	return &Tree{folders: make(map[string]*folder)}
+}
+
+// AddFile adds the file found in `path` (relative to the repository) and its modification time. Files that don't
+// belong to the Conan layout are ignored.
+func (tree *Tree) AddFile(path string, modified time.Time) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 5 {
+		return
+	}
+	recipe := tree.folder(strings.Join(parts[:4], "/"), parts[1]+"/"+parts[2]+"@"+parts[0]+"/"+parts[3])
+	if len(parts) == 5 {
+		if parts[4] == "index.json" {
+			recipe.hasIndex = true
+		}
+		return
+	}
+
+	// Any file inside the folder of a recipe revision (also inside its packages) means the revision exists
+	rrev := recipe.revision(parts[4], modified)
+	if parts[5] != "package" {
+		if parts[5] == "export" && len(parts) == 7 {
+			rrev.files[parts[6]] = true
+		}
+		return
+	}
+	if len(parts) < 8 {
+		return
+	}
+	pkg := tree.folder(strings.Join(parts[:7], "/"), recipe.reference+"#"+parts[4]+":"+parts[6])
+	if len(parts) == 8 {
+		if parts[7] == "index.json" {
+			pkg.hasIndex = true
+		}
+		return
+	}
+	prev := pkg.revision(parts[7], modified)
+	if len(parts) == 9 {
+		prev.files[parts[8]] = true
+	}
+}
+
+func (tree *Tree) folder(path string, reference string) *folder {
+	f, ok := tree.folders[path]
+	if !ok {
+		f = &folder{reference: reference, revisions: make(map[string]*revision)}
+		tree.folders[path] = f
+	}
+	return f
+}
+
+func (f *folder) revision(name string, modified time.Time) *revision {
+	r, ok := f.revisions[name]
+	if !ok {
+		r = &revision{files: make(map[string]bool)}
+		f.revisions[name] = r
+	}
+	if modified.After(r.modified) {
+		r.modified = modified
+	}
+	return r
+}
+
+func (f *folder) isPackage() bool {
+	return strings.Contains(f.reference, ":")
+}
+
+// IndexPaths returns the paths (relative to the repository) of the 'index.json' files found, sorted.
+func (tree *Tree) IndexPaths() []string {
+	paths := []string{}
+	for path, f := range tree.folders {
+		if f.hasIndex {
+			paths = append(paths, path+"/index.json")
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Check returns the issues found comparing the folders with the `indexes` (contents of the 'index.json' files, the
+// key is the path of the file relative to the repository). Issues are sorted by path and revision.
+func (tree *Tree) Check(indexes map[string]*types.RtIndexJSON) []Issue {
+	issues := []Issue{}
+	for path, f := range tree.folders {
+		index, ok := indexes[path+"/index.json"]
+		if !ok {
+			if len(f.revisions) > 0 {
+				issues = append(issues, Issue{Kind: MissingIndex, Path: path})
+			}
+		} else {
+			listed := make(map[string]bool)
+			for _, r := range index.Revisions {
+				listed[r.Revision] = true
+				if _, ok := f.revisions[r.Revision]; !ok {
+					issues = append(issues, Issue{Kind: MissingFolder, Path: path, Revision: r.Revision})
+				}
+			}
+			for name := range f.revisions {
+				if !listed[name] {
+					issues = append(issues, Issue{Kind: UnlistedFolder, Path: path, Revision: name})
+				}
+			}
+		}
+
+		for name, r := range f.revisions {
+			if !r.files["conanmanifest.txt"] {
+				issues = append(issues, Issue{Kind: MissingManifest, Path: path, Revision: name})
+			}
+			if f.isPackage() && !r.files["conaninfo.txt"] {
+				issues = append(issues, Issue{Kind: MissingConanInfo, Path: path, Revision: name})
+			}
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		if issues[i].Revision != issues[j].Revision {
+			return issues[i].Revision < issues[j].Revision
+		}
+		return issues[i].Kind < issues[j].Kind
+	})
+	return issues
+}
+
+// FixIndex returns the contents of the 'index.json' for the folder `path` (relative to the repository) listing only
+// the revisions whose folder exists. The given `index` (nil if there is no file) is not modified. Revisions added
+// use the newest modification time of their files.
+func (tree *Tree) FixIndex(path string, index *types.RtIndexJSON) *types.RtIndexJSON {
+	f, ok := tree.folders[path]
+	if !ok {
+		return nil
+	}
+	fixed := &types.RtIndexJSON{Revisions: []types.RtRevisionsData{}}
+	if index != nil {
+		fixed.Reference = index.Reference
+		fixed.PackageReference = index.PackageReference
+		for _, r := range index.Revisions {
+			if _, ok := f.revisions[r.Revision]; ok {
+				fixed.Revisions = append(fixed.Revisions, r)
+			}
+		}
+	} else if f.isPackage() {
+		fixed.PackageReference = f.reference
+	} else {
+		fixed.Reference = f.reference
+	}
+
+	added := []types.RtRevisionsData{}
+	for name, r := range f.revisions {
+		added = append(added, types.RtRevisionsData{Revision: name, Time: types.RtTimestamp{Time: r.modified}})
+	}
+	fixed.AddRevisions(added)
+	return fixed
+}
@@ -0,0 +1,103 @@
+package verify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jgsogo/jcli-conan-center/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTree() *Tree {
+	t1 := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	tree := NewTree()
+	for _, path := range []string{
+		"_/b2/4.0.0/_/index.json",
+		"_/b2/4.0.0/_/rrev1/export/conanfile.py",
+		"_/b2/4.0.0/_/rrev1/export/conanmanifest.txt",
+		"_/b2/4.0.0/_/rrev1/package/pkgid/index.json",
+		"_/b2/4.0.0/_/rrev1/package/pkgid/prev1/conaninfo.txt",
+		"_/b2/4.0.0/_/rrev1/package/pkgid/prev1/conanmanifest.txt",
+		"_/b2/4.0.0/_/rrev1/package/pkgid/prev2/conan_package.tgz",
+		"_/b2/4.0.0/_/rrev1/package/other/prev/conaninfo.txt",
+		"_/b2/4.0.0/_/rrev1/package/other/prev/conanmanifest.txt",
+		"README.md",
+	} {
+		tree.AddFile(path, t1)
+	}
+	tree.AddFile("_/b2/4.0.0/_/rrev2/export/conanfile.py", t1)
+	tree.AddFile("_/b2/4.0.0/_/rrev2/export/conanmanifest.txt", t2)
+	return tree
+}
+
+func TestCheck(t *testing.T) {
+	tree := newTree()
+	assert.Equal(t, []string{"_/b2/4.0.0/_/index.json", "_/b2/4.0.0/_/rrev1/package/pkgid/index.json"}, tree.IndexPaths())
+
+	indexes := map[string]*types.RtIndexJSON{
+		"_/b2/4.0.0/_/index.json": {Reference: "b2/4.0.0@_/_", Revisions: []types.RtRevisionsData{
+			{Revision: "rrev3"}, {Revision: "rrev1"},
+		}},
+		"_/b2/4.0.0/_/rrev1/package/pkgid/index.json": {PackageReference: "b2/4.0.0@_/_#rrev1:pkgid", Revisions: []types.RtRevisionsData{
+			{Revision: "prev1"}, {Revision: "prev2"},
+		}},
+	}
+	assert.Equal(t, []Issue{
+		{Kind: UnlistedFolder, Path: "_/b2/4.0.0/_", Revision: "rrev2"},
+		{Kind: MissingFolder, Path: "_/b2/4.0.0/_", Revision: "rrev3"},
+		{Kind: MissingIndex, Path: "_/b2/4.0.0/_/rrev1/package/other"},
+		{Kind: MissingConanInfo, Path: "_/b2/4.0.0/_/rrev1/package/pkgid", Revision: "prev2"},
+		{Kind: MissingManifest, Path: "_/b2/4.0.0/_/rrev1/package/pkgid", Revision: "prev2"},
+	}, tree.Check(indexes))
+
+	assert.Equal(t, "unlisted-folder: _/b2/4.0.0/_/rrev2", Issue{Kind: UnlistedFolder, Path: "_/b2/4.0.0/_", Revision: "rrev2"}.String())
+	assert.Equal(t, "missing-index: _/b2/4.0.0/_", Issue{Kind: MissingIndex, Path: "_/b2/4.0.0/_"}.String())
+	assert.True(t, MissingIndex.Fixable())
+	assert.False(t, MissingManifest.Fixable())
+}
+
+func TestFixIndex(t *testing.T) {
+	tree := newTree()
+	t0 := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	index := &types.RtIndexJSON{Reference: "b2/4.0.0@_/_", Revisions: []types.RtRevisionsData{
+		{Revision: "rrev3"}, {Revision: "rrev1", Time: types.RtTimestamp{Time: t0}},
+	}}
+	fixed := tree.FixIndex("_/b2/4.0.0/_", index)
+	assert.Equal(t, "b2/4.0.0@_/_", fixed.Reference)
+	assert.Equal(t, 2, len(fixed.Revisions))
+	assert.Equal(t, "rrev2", fixed.Revisions[0].Revision)
+	assert.Equal(t, time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), fixed.Revisions[0].Time.Time)
+	assert.Equal(t, "rrev1", fixed.Revisions[1].Revision)
+	assert.Equal(t, t0, fixed.Revisions[1].Time.Time)
+	assert.Equal(t, 2, len(index.Revisions))
+
+	fixed = tree.FixIndex("_/b2/4.0.0/_/rrev1/package/other", nil)
+	assert.Equal(t, "b2/4.0.0@_/_#rrev1:other", fixed.PackageReference)
+	assert.Equal(t, "", fixed.Reference)
+	assert.Equal(t, []types.RtRevisionsData{{Revision: "prev", Time: types.RtTimestamp{Time: time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)}}}, fixed.Revisions)
+
+	assert.Nil(t, tree.FixIndex("_/zlib/1.2.11/_", nil))
+}
+
+func TestPackagesOnlyRevision(t *testing.T) {
+	t1 := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	tree := NewTree()
+	tree.AddFile("_/b2/4.0.0/_/index.json", t1)
+	tree.AddFile("_/b2/4.0.0/_/rrev1/package/pkgid/index.json", t1)
+	tree.AddFile("_/b2/4.0.0/_/rrev1/package/pkgid/prev/conaninfo.txt", t1)
+	tree.AddFile("_/b2/4.0.0/_/rrev1/package/pkgid/prev/conanmanifest.txt", t1)
+
+	indexes := map[string]*types.RtIndexJSON{
+		"_/b2/4.0.0/_/index.json": {Reference: "b2/4.0.0@_/_", Revisions: []types.RtRevisionsData{{Revision: "rrev1"}}},
+		"_/b2/4.0.0/_/rrev1/package/pkgid/index.json": {PackageReference: "b2/4.0.0@_/_#rrev1:pkgid", Revisions: []types.RtRevisionsData{
+			{Revision: "prev"},
+		}},
+	}
+	assert.Equal(t, []Issue{
+		{Kind: MissingManifest, Path: "_/b2/4.0.0/_", Revision: "rrev1"},
+	}, tree.Check(indexes))
+
+	fixed := tree.FixIndex("_/b2/4.0.0/_", indexes["_/b2/4.0.0/_/index.json"])
+	assert.Equal(t, []types.RtRevisionsData{{Revision: "rrev1"}}, fixed.Revisions)
+}